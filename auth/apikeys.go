@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// APIKey is an entry of the API keys file, a YAML or JSON list such as
+//
+//	[{name: ci, key: 6f1c0b2e..., scopes: [messages.write]}]
+//
+// Name becomes the subject of the principal.
+type APIKey struct {
+	Name   string   `yaml:"name"`
+	Key    string   `yaml:"key"`
+	Scopes []string `yaml:"scopes"`
+}
+
+// readAPIKeys returns the principals of the keys in file by the SHA-256 of
+// the key, so looking one up does not compare secrets byte by byte.
+func readAPIKeys(file string) (map[[sha256.Size]byte]*Principal, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var list []APIKey
+	if err := yaml.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	keys := make(map[[sha256.Size]byte]*Principal, len(list))
+	for i, k := range list {
+		if k.Name == "" || k.Key == "" {
+			return nil, fmt.Errorf("%s: entry %d needs a name and a key", file, i)
+		}
+		sum := sha256.Sum256([]byte(k.Key))
+		if _, ok := keys[sum]; ok {
+			return nil, fmt.Errorf("%s: key of %q is used twice", file, k.Name)
+		}
+		keys[sum] = &Principal{Subject: k.Name, Kind: KindAPIKey, Scopes: k.Scopes}
+	}
+	return keys, nil
+}
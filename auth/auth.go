@@ -0,0 +1,197 @@
+// Package auth authenticates calls by a bearer JWT or an API key and checks
+// the caller against a per-method policy.
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/shin5ok/proto-grpc-simple/logging"
+	"github.com/shin5ok/proto-grpc-simple/rpcerror"
+)
+
+// Credentials are read from these metadata keys:
+//
+//	authorization: Bearer <JWT>
+//	x-api-key:     <key>
+const (
+	AuthorizationKey = "authorization"
+	APIKeyKey        = "x-api-key"
+)
+
+// Kinds of principal.
+const (
+	KindJWT    = "jwt"
+	KindAPIKey = "api_key"
+)
+
+// Principal is the authenticated caller.
+type Principal struct {
+	Subject string
+	Kind    string
+	// Issuer is the iss claim of a JWT.
+	Issuer string
+	Scopes []string
+}
+
+// HasScope reports whether the principal was granted scope.
+func (p *Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+// NewContext returns ctx carrying p.
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal of the call, if it was authenticated.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok
+}
+
+// Options configure an Authenticator. At least one of JWKSFile, HMACKeyFile
+// and APIKeysFile must be set.
+type Options struct {
+	// JWKSFile holds the public keys of RS*, PS* and ES* tokens.
+	JWKSFile string
+	// HMACKeyFile holds the shared secret of HS* tokens.
+	HMACKeyFile string
+	// Issuer and Audience, when set, must match the iss and aud claims.
+	Issuer   string
+	Audience string
+	// APIKeysFile lists API keys, see APIKey.
+	APIKeysFile string
+	Policy      Policy
+}
+
+// Authenticator checks the credentials of calls against a Policy.
+type Authenticator struct {
+	jwt     *verifier
+	apiKeys map[[sha256.Size]byte]*Principal
+	policy  Policy
+}
+
+// New reads the key files named by o.
+func New(o Options) (*Authenticator, error) {
+	if o.JWKSFile == "" && o.HMACKeyFile == "" && o.APIKeysFile == "" {
+		return nil, errors.New("auth needs a JWKS file, an HMAC key file or an API keys file")
+	}
+	a := &Authenticator{policy: o.Policy}
+	if o.JWKSFile != "" || o.HMACKeyFile != "" {
+		v, err := newVerifier(o.JWKSFile, o.HMACKeyFile, o.Issuer, o.Audience)
+		if err != nil {
+			return nil, err
+		}
+		a.jwt = v
+	}
+	if o.APIKeysFile != "" {
+		keys, err := readAPIKeys(o.APIKeysFile)
+		if err != nil {
+			return nil, err
+		}
+		a.apiKeys = keys
+	}
+	return a, nil
+}
+
+// Authenticate checks the credentials in the incoming metadata of ctx against
+// the rule for method and returns ctx with the principal attached to it, its
+// span and its request logger. Calls without credentials fail with
+// Unauthenticated and calls lacking a scope with PermissionDenied; public
+// methods let anyone through.
+func (a *Authenticator) Authenticate(ctx context.Context, method string) (context.Context, error) {
+	rule := a.policy.Rule(method)
+	p, err := a.principal(ctx)
+	if err != nil {
+		if rule.Public {
+			return ctx, nil
+		}
+		logging.Ctx(ctx).Info().Err(err).Msg("authentication failed")
+		return ctx, rpcerror.New(codes.Unauthenticated, rpcerror.ReasonInvalidCredentials, "invalid credentials")
+	}
+	if p == nil {
+		if rule.Public {
+			return ctx, nil
+		}
+		return ctx, rpcerror.New(codes.Unauthenticated, rpcerror.ReasonMissingCredentials, "missing credentials")
+	}
+
+	ctx = withPrincipal(ctx, p)
+	if !rule.Public {
+		for _, s := range rule.Scopes {
+			if !p.HasScope(s) {
+				logging.Ctx(ctx).Info().Str("scope", s).Msg("permission denied")
+				return ctx, rpcerror.New(codes.PermissionDenied, rpcerror.ReasonMissingScope, "missing scope "+s,
+					rpcerror.WithMetadata("scope", s))
+			}
+		}
+	}
+	return ctx, nil
+}
+
+// principal returns the caller named by the credentials of ctx, or nil when
+// there are none. Bearer tokens are only looked at when JWTs are configured,
+// so a token meant for a proxy in front of the server does not get in the way
+// of an API key.
+func (a *Authenticator) principal(ctx context.Context) (*Principal, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if a.jwt != nil {
+		if v := md.Get(AuthorizationKey); len(v) > 0 {
+			scheme, token, _ := strings.Cut(v[0], " ")
+			if !strings.EqualFold(scheme, "bearer") || token == "" {
+				return nil, errors.New("authorization is not a bearer token")
+			}
+			return a.jwt.verify(strings.TrimSpace(token))
+		}
+	}
+	if a.apiKeys != nil {
+		if v := md.Get(APIKeyKey); len(v) > 0 {
+			if p, ok := a.apiKeys[sha256.Sum256([]byte(v[0]))]; ok {
+				return p, nil
+			}
+			return nil, errors.New("unknown API key")
+		}
+	}
+	return nil, nil
+}
+
+func withPrincipal(ctx context.Context, p *Principal) context.Context {
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("enduser.id", p.Subject),
+		attribute.String("enduser.scope", strings.Join(p.Scopes, " ")),
+	)
+	l := logging.Ctx(ctx).With().Str("enduser.id", p.Subject).Str("auth.kind", p.Kind).Logger()
+	return NewContext(logging.NewContext(ctx, l), p)
+}
+
+func (a *Authenticator) UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := a.Authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a *Authenticator) StreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.Authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, logging.StreamWithContext(ctx, ss))
+}
@@ -0,0 +1,215 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const hmacKey = "0123456789abcdef0123456789abcdef"
+
+func writeFile(t *testing.T, name, content string) string {
+	file := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, c jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, c)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	s, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func incoming(kv ...string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(kv...))
+}
+
+func code(err error) codes.Code {
+	return status.Code(err)
+}
+
+func TestParsePolicy(t *testing.T) {
+	p, err := ParsePolicy("simple.Simple", []string{
+		"PingPong=public",
+		"PutMessage=scope:messages.write scope:messages.admin",
+		"/grpc.health.v1.Health/=public",
+		"/grpc.health.v1.Health/Watch=authenticated",
+	}, "authenticated")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for method, want := range map[string]string{
+		"/simple.Simple/PingPong":        "public",
+		"/simple.Simple/PutMessage":      "scope:messages.write scope:messages.admin",
+		"/simple.Simple/GetMessage":      "authenticated",
+		"/grpc.health.v1.Health/Check":   "public",
+		"/grpc.health.v1.Health/Watch":   "authenticated",
+		"/grpc.other.Service/ListThings": "authenticated",
+	} {
+		if got := p.Rule(method).String(); got != want {
+			t.Errorf("%s: got %s, want %s", method, got, want)
+		}
+	}
+
+	for _, bad := range [][]string{{"PingPong"}, {"=public"}, {"PingPong=everyone"}, {"PutMessage=messages.write"}} {
+		if _, err := ParsePolicy("simple.Simple", bad, ""); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
+
+func TestHMAC(t *testing.T) {
+	policy, _ := ParsePolicy("simple.Simple", []string{"PingPong=public", "PutMessage=scope:messages.write"}, "authenticated")
+	a, err := New(Options{HMACKeyFile: writeFile(t, "key", hmacKey+"\n"), Issuer: "test", Audience: "simple", Policy: policy})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := time.Now().Add(time.Hour).Unix()
+	valid := sign(t, jwt.SigningMethodHS256, []byte(hmacKey), "", jwt.MapClaims{
+		"sub": "alice", "iss": "test", "aud": "simple", "exp": exp, "scope": "messages.read messages.write",
+	})
+	ctx, err := a.Authenticate(incoming("authorization", "Bearer "+valid), "/simple.Simple/PutMessage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, ok := FromContext(ctx)
+	if !ok || p.Subject != "alice" || p.Kind != KindJWT || !p.HasScope("messages.write") {
+		t.Errorf("got %+v", p)
+	}
+
+	readOnly := sign(t, jwt.SigningMethodHS256, []byte(hmacKey), "", jwt.MapClaims{
+		"sub": "bob", "iss": "test", "aud": "simple", "exp": exp, "scp": []string{"messages.read"},
+	})
+	if _, err := a.Authenticate(incoming("authorization", "Bearer "+readOnly), "/simple.Simple/PutMessage"); code(err) != codes.PermissionDenied {
+		t.Errorf("missing scope: got %v", err)
+	}
+	if _, err := a.Authenticate(incoming("authorization", "Bearer "+readOnly), "/simple.Simple/GetMessage"); err != nil {
+		t.Errorf("authenticated method: got %v", err)
+	}
+
+	for name, token := range map[string]string{
+		"expired":      sign(t, jwt.SigningMethodHS256, []byte(hmacKey), "", jwt.MapClaims{"sub": "alice", "iss": "test", "aud": "simple", "exp": time.Now().Add(-time.Hour).Unix()}),
+		"no exp":       sign(t, jwt.SigningMethodHS256, []byte(hmacKey), "", jwt.MapClaims{"sub": "alice", "iss": "test", "aud": "simple"}),
+		"wrong issuer": sign(t, jwt.SigningMethodHS256, []byte(hmacKey), "", jwt.MapClaims{"sub": "alice", "iss": "other", "aud": "simple", "exp": exp}),
+		"wrong aud":    sign(t, jwt.SigningMethodHS256, []byte(hmacKey), "", jwt.MapClaims{"sub": "alice", "iss": "test", "aud": "other", "exp": exp}),
+		"wrong key":    sign(t, jwt.SigningMethodHS256, []byte("another key of at least 32 bytes!"), "", jwt.MapClaims{"sub": "alice", "iss": "test", "aud": "simple", "exp": exp}),
+		"none":         sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "", jwt.MapClaims{"sub": "alice", "iss": "test", "aud": "simple", "exp": exp}),
+		"garbage":      "not.a.token",
+	} {
+		if _, err := a.Authenticate(incoming("authorization", "Bearer "+token), "/simple.Simple/GetMessage"); code(err) != codes.Unauthenticated {
+			t.Errorf("%s: got %v", name, err)
+		}
+	}
+
+	if _, err := a.Authenticate(context.Background(), "/simple.Simple/GetMessage"); code(err) != codes.Unauthenticated {
+		t.Errorf("no credentials: got %v", err)
+	}
+	if _, err := a.Authenticate(context.Background(), "/simple.Simple/PingPong"); err != nil {
+		t.Errorf("public method: got %v", err)
+	}
+	if _, err := a.Authenticate(incoming("authorization", "Basic YWxpY2U6"), "/simple.Simple/PingPong"); err != nil {
+		t.Errorf("public method with bad credentials: got %v", err)
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	jwks, _ := json.Marshal(map[string]interface{}{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa", "use": "sig", "n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+		{"kty": "EC", "kid": "ec", "crv": "P-256", "x": b64(ecKey.X.Bytes()), "y": b64(ecKey.Y.Bytes())},
+		{"kty": "RSA", "kid": "enc", "use": "enc", "n": "AQAB", "e": "AQAB"},
+	}})
+	a, err := New(Options{JWKSFile: writeFile(t, "jwks.json", string(jwks))})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := jwt.MapClaims{"sub": "svc", "exp": time.Now().Add(time.Hour).Unix()}
+	for name, token := range map[string]string{
+		"RS256": sign(t, jwt.SigningMethodRS256, rsaKey, "rsa", c),
+		"ES256": sign(t, jwt.SigningMethodES256, ecKey, "ec", c),
+	} {
+		if _, err := a.Authenticate(incoming("authorization", "Bearer "+token), "/simple.Simple/GetMessage"); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+
+	for name, token := range map[string]string{
+		"unknown kid": sign(t, jwt.SigningMethodRS256, rsaKey, "other", c),
+		"wrong kid":   sign(t, jwt.SigningMethodES256, ecKey, "rsa", c),
+		// An HMAC token must not be checked against the public RSA key.
+		"HS256": sign(t, jwt.SigningMethodHS256, rsaKey.N.Bytes(), "rsa", c),
+	} {
+		if _, err := a.Authenticate(incoming("authorization", "Bearer "+token), "/simple.Simple/GetMessage"); code(err) != codes.Unauthenticated {
+			t.Errorf("%s: got %v", name, err)
+		}
+	}
+}
+
+func TestAPIKeys(t *testing.T) {
+	keys := writeFile(t, "keys.yaml", `
+- name: ci
+  key: ci-secret
+  scopes: [messages.write]
+- name: reader
+  key: reader-secret
+`)
+	policy, _ := ParsePolicy("simple.Simple", []string{"PutMessage=scope:messages.write"}, "authenticated")
+	a, err := New(Options{APIKeysFile: keys, Policy: policy})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, err := a.Authenticate(incoming("x-api-key", "ci-secret"), "/simple.Simple/PutMessage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := FromContext(ctx); p == nil || p.Subject != "ci" || p.Kind != KindAPIKey {
+		t.Errorf("got %+v", p)
+	}
+	if _, err := a.Authenticate(incoming("x-api-key", "reader-secret"), "/simple.Simple/PutMessage"); code(err) != codes.PermissionDenied {
+		t.Errorf("missing scope: got %v", err)
+	}
+	if _, err := a.Authenticate(incoming("x-api-key", "wrong"), "/simple.Simple/GetMessage"); code(err) != codes.Unauthenticated {
+		t.Errorf("unknown key: got %v", err)
+	}
+	// Without JWTs configured a bearer token, e.g. for Cloud Run IAM, is not looked at.
+	if _, err := a.Authenticate(incoming("authorization", "Bearer x", "x-api-key", "reader-secret"), "/simple.Simple/GetMessage"); err != nil {
+		t.Errorf("bearer token next to an API key: got %v", err)
+	}
+
+	if _, err := New(Options{APIKeysFile: writeFile(t, "dup.yaml", "[{name: a, key: k}, {name: b, key: k}]")}); err == nil {
+		t.Error("duplicate keys accepted")
+	}
+	if _, err := New(Options{}); err == nil {
+		t.Error("no credentials accepted")
+	}
+}
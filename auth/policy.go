@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rule is what a method requires of the caller.
+type Rule struct {
+	// Public methods take no credentials; valid ones are still attached to the context.
+	Public bool
+	// Scopes must all be granted to the caller.
+	Scopes []string
+}
+
+// ParseRule reads "public", "authenticated" or space separated "scope:<name>" entries.
+func ParseRule(s string) (Rule, error) {
+	switch s = strings.TrimSpace(s); s {
+	case "public":
+		return Rule{Public: true}, nil
+	case "authenticated", "":
+		return Rule{}, nil
+	}
+	var r Rule
+	for _, f := range strings.Fields(s) {
+		scope := strings.TrimPrefix(f, "scope:")
+		if scope == f || scope == "" {
+			return Rule{}, fmt.Errorf("invalid rule %q: want public, authenticated or scope:<name>", s)
+		}
+		r.Scopes = append(r.Scopes, scope)
+	}
+	return r, nil
+}
+
+func (r Rule) String() string {
+	if r.Public {
+		return "public"
+	}
+	if len(r.Scopes) == 0 {
+		return "authenticated"
+	}
+	return "scope:" + strings.Join(r.Scopes, " scope:")
+}
+
+// Policy maps methods to rules. Keys are full method names
+// ("/simple.Simple/PingPong") or services ending in a slash
+// ("/grpc.health.v1.Health/"); a method rule wins over its service rule.
+type Policy struct {
+	Rules   map[string]Rule
+	Default Rule
+}
+
+// ParsePolicy reads "Method=rule" entries. Methods without a leading slash
+// belong to service, e.g. "PingPong" is "/simple.Simple/PingPong" for
+// service "simple.Simple". Later entries win over earlier ones.
+func ParsePolicy(service string, entries []string, def string) (Policy, error) {
+	d, err := ParseRule(def)
+	if err != nil {
+		return Policy{}, fmt.Errorf("default policy: %w", err)
+	}
+	p := Policy{Rules: map[string]Rule{}, Default: d}
+	for _, e := range entries {
+		method, rule, ok := strings.Cut(e, "=")
+		method = strings.TrimSpace(method)
+		if !ok || method == "" {
+			return Policy{}, fmt.Errorf("invalid policy entry %q: want Method=rule", e)
+		}
+		if !strings.HasPrefix(method, "/") {
+			method = "/" + service + "/" + method
+		}
+		r, err := ParseRule(rule)
+		if err != nil {
+			return Policy{}, fmt.Errorf("policy for %s: %w", method, err)
+		}
+		p.Rules[method] = r
+	}
+	return p, nil
+}
+
+// Rule returns the rule for a full method name.
+func (p Policy) Rule(method string) Rule {
+	if r, ok := p.Rules[method]; ok {
+		return r
+	}
+	if i := strings.LastIndex(method, "/"); i > 0 {
+		if r, ok := p.Rules[method[:i+1]]; ok {
+			return r
+		}
+	}
+	return p.Default
+}
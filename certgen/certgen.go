@@ -0,0 +1,123 @@
+package certgen
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"time"
+)
+
+// Cert is a certificate with its private key.
+type Cert struct {
+	Cert *x509.Certificate
+	Key  *ecdsa.PrivateKey
+}
+
+// Options describe a certificate to issue. Hosts become DNS or IP SANs.
+// A zero NotBefore means now, a zero NotAfter a year after NotBefore.
+type Options struct {
+	CommonName string
+	Hosts      []string
+	NotBefore  time.Time
+	NotAfter   time.Time
+	// Client issues a certificate for client authentication instead of a server one.
+	Client bool
+}
+
+// NewCA creates a self-signed CA.
+func NewCA(o Options) (*Cert, error) {
+	template, key, err := newTemplate(o)
+	if err != nil {
+		return nil, err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+	template.ExtKeyUsage = nil
+	return create(template, template, key, key)
+}
+
+// Issue creates a certificate signed by ca.
+func (ca *Cert) Issue(o Options) (*Cert, error) {
+	template, key, err := newTemplate(o)
+	if err != nil {
+		return nil, err
+	}
+	return create(template, ca.Cert, key, ca.Key)
+}
+
+func newTemplate(o Options) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	notBefore := o.NotBefore
+	if notBefore.IsZero() {
+		notBefore = time.Now().Add(-time.Minute)
+	}
+	notAfter := o.NotAfter
+	if notAfter.IsZero() {
+		notAfter = notBefore.AddDate(1, 0, 0)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: o.CommonName},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if o.Client {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	}
+	for _, h := range o.Hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, h)
+		}
+	}
+	return template, key, nil
+}
+
+func create(template, parent *x509.Certificate, key, parentKey *ecdsa.PrivateKey) (*Cert, error) {
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &Cert{Cert: cert, Key: key}, nil
+}
+
+// Write saves the certificate and key as PEM, the key readable by the owner only.
+func (c *Cert) Write(certFile, keyFile string) error {
+	der, err := x509.MarshalECPrivateKey(c.Key)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.Cert.Raw}), 0o644); err != nil {
+		return err
+	}
+	return os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0o600)
+}
+
+// TLSCertificate returns c for use in a tls.Config.
+func (c *Cert) TLSCertificate() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.Cert.Raw}, PrivateKey: c.Key, Leaf: c.Cert}
+}
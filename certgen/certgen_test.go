@@ -0,0 +1,81 @@
+package certgen
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func verify(ca, c *Cert, host string, usage x509.ExtKeyUsage) error {
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Cert)
+	_, err := c.Cert.Verify(x509.VerifyOptions{
+		DNSName:   host,
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{usage},
+	})
+	return err
+}
+
+func TestIssue(t *testing.T) {
+	ca, err := NewCA(Options{CommonName: "test CA"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, _ := NewCA(Options{CommonName: "other CA"})
+
+	server, err := ca.Issue(Options{CommonName: "localhost", Hosts: []string{"localhost", "127.0.0.1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := verify(ca, server, "localhost", x509.ExtKeyUsageServerAuth); err != nil {
+		t.Error(err)
+	}
+	if err := verify(ca, server, "127.0.0.1", x509.ExtKeyUsageServerAuth); err != nil {
+		t.Error(err)
+	}
+	if err := verify(ca, server, "example.com", x509.ExtKeyUsageServerAuth); err == nil {
+		t.Error("verified for a host that is not a SAN")
+	}
+	if err := verify(other, server, "localhost", x509.ExtKeyUsageServerAuth); err == nil {
+		t.Error("verified against another CA")
+	}
+
+	expired, _ := ca.Issue(Options{
+		CommonName: "localhost",
+		Hosts:      []string{"localhost"},
+		NotBefore:  time.Now().Add(-48 * time.Hour),
+		NotAfter:   time.Now().Add(-24 * time.Hour),
+	})
+	if err := verify(ca, expired, "localhost", x509.ExtKeyUsageServerAuth); err == nil {
+		t.Error("expired certificate verified")
+	}
+
+	client, _ := ca.Issue(Options{CommonName: "client", Client: true})
+	if err := verify(ca, client, "", x509.ExtKeyUsageClientAuth); err != nil {
+		t.Error(err)
+	}
+	if err := verify(ca, client, "", x509.ExtKeyUsageServerAuth); err == nil {
+		t.Error("client certificate verified for server auth")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	ca, _ := NewCA(Options{CommonName: "test CA"})
+	c, _ := ca.Issue(Options{CommonName: "localhost", Hosts: []string{"localhost"}})
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "server.pem"), filepath.Join(dir, "server-key.pem")
+	if err := c.Write(certFile, keyFile); err != nil {
+		t.Fatal(err)
+	}
+	pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(pair.Certificate[0]) != string(c.Cert.Raw) {
+		t.Error("written certificate differs")
+	}
+}
@@ -11,8 +11,12 @@ import (
 	"time"
 
 	"github.com/shin5ok/proto-grpc-simple/pb"
+	"github.com/shin5ok/proto-grpc-simple/rpcerror"
+	"github.com/shin5ok/proto-grpc-simple/tlsconfig"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 var (
@@ -25,6 +29,17 @@ func main() {
 	insecure := flag.Bool("insecure", false, "")
 	stdout := flag.Bool("stdout", false, "")
 	mode := flag.String("mode", "list-message", "")
+	interval := flag.Duration("interval", 0, "")
+	jitter := flag.Duration("jitter", 0, "")
+	initialDelay := flag.Duration("initial-delay", 0, "")
+	payloadSize := flag.Int("payload-size", 0, "")
+	caFile := flag.String("ca-file", "", "PEM CA to verify the server with, instead of the system roots")
+	certFile := flag.String("cert-file", "", "PEM client certificate for mutual TLS")
+	keyFile := flag.String("key-file", "", "PEM key of the client certificate")
+	serverName := flag.String("server-name", "", "name to verify the server certificate against, instead of the host")
+	skipVerify := flag.Bool("skip-verify", false, "do not verify the server certificate")
+	token := flag.String("token", "", "bearer token to send as authorization")
+	apiKey := flag.String("api-key", "", "API key to send as x-api-key")
 
 	flag.Parse()
 
@@ -33,12 +48,26 @@ func main() {
 	if *insecure {
 		conn, err = grpc.Dial(*host, grpc.WithInsecure())
 	} else {
-		creds := credentials.NewTLS(&tls.Config{
-			InsecureSkipVerify: true,
-		})
+		config := &tls.Config{
+			ServerName:         *serverName,
+			InsecureSkipVerify: *skipVerify,
+		}
+		if *caFile != "" {
+			config.RootCAs, err = tlsconfig.CertPool(*caFile)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+		if *certFile != "" {
+			cert, err := tls.LoadX509KeyPair(*certFile, *keyFile)
+			if err != nil {
+				log.Fatal(err)
+			}
+			config.Certificates = []tls.Certificate{cert}
+		}
 
 		opts := []grpc.DialOption{
-			grpc.WithTransportCredentials(creds),
+			grpc.WithTransportCredentials(credentials.NewTLS(config)),
 		}
 		conn, err = grpc.Dial(*host, opts...)
 	}
@@ -50,11 +79,32 @@ func main() {
 	}
 	defer conn.Close()
 
+	base := context.Background()
+	if *token != "" {
+		base = metadata.AppendToOutgoingContext(base, "authorization", "Bearer "+*token)
+	}
+	if *apiKey != "" {
+		base = metadata.AppendToOutgoingContext(base, "x-api-key", *apiKey)
+	}
+
 	if *mode == "list-message" {
-		ctx := context.Background()
+		ctx := base
 		start := time.Now()
-		request := &pb.Request{Number: int32(*number)}
-		stream, _ := client.ListMessage(ctx, request)
+		request := &pb.Request{
+			Number:       int32(*number),
+			Jitter:       durationpb.New(*jitter),
+			InitialDelay: durationpb.New(*initialDelay),
+			PayloadSize:  int32(*payloadSize),
+		}
+		flag.Visit(func(f *flag.Flag) {
+			if f.Name == "interval" {
+				request.Interval = durationpb.New(*interval)
+			}
+		})
+		stream, err := client.ListMessage(ctx, request)
+		if err != nil {
+			log.Fatal(rpcerror.Decode(err))
+		}
 		for {
 			reponse, err := stream.Recv()
 			if err == io.EOF {
@@ -64,23 +114,54 @@ func main() {
 				os.Exit(0)
 			}
 			if err != nil {
-				log.Fatal(err)
+				log.Fatal(rpcerror.Decode(err))
 			}
 			if *stdout {
 				fmt.Println(reponse.Message)
 			}
 		}
 	} else if *mode == "put-message" {
-		ctx := context.Background()
+		ctx := base
 		start := time.Now()
 
 		message := "foo"
 		for id := 0; id < *number; id++ {
 			name := &pb.Name{Id: int32(id), Text: "foo"}
-			client.PutMessage(ctx, &pb.Message{Name: name, Message: message})
+			if _, err := client.PutMessage(ctx, &pb.Message{Name: name, Message: message}); err != nil && *stdout {
+				fmt.Println(rpcerror.Decode(err))
+			}
+		}
+		finish := time.Now()
+		delta := finish.Sub(start)
+		fmt.Printf("%s\n", delta)
+
+	} else if *mode == "bulk-put-message" {
+		ctx := base
+		start := time.Now()
+
+		stream, err := client.BulkPutMessageV2(ctx)
+		if err != nil {
+			log.Fatal(rpcerror.Decode(err))
+		}
+		for id := 0; id < *number; id++ {
+			name := &pb.Name{Id: int32(id), Text: "foo"}
+			// A failed send means the server ended the call; CloseAndRecv tells why.
+			if err := stream.Send(&pb.Message{Name: name, Message: "foo"}); err != nil {
+				break
+			}
+		}
+		summary, err := stream.CloseAndRecv()
+		if err != nil {
+			log.Fatal(rpcerror.Decode(err))
 		}
 		finish := time.Now()
 		delta := finish.Sub(start)
+		if *stdout {
+			fmt.Printf("accepted: %d, rejected: %d\n", summary.Accepted, summary.Rejected)
+			for _, e := range summary.Errors {
+				fmt.Printf("  #%d %d %s\n", e.Index, e.Code, e.Message)
+			}
+		}
 		fmt.Printf("%s\n", delta)
 
 	}
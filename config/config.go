@@ -0,0 +1,611 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is everything the server reads at startup.
+//
+// Values are applied in order: the defaults from Default, then the file named
+// by -config or CONFIG_FILE (YAML, or JSON, which is valid YAML), then
+// environment variables, then command line flags. Each field lists its
+// environment variable and flag.
+type Config struct {
+	Port        int `yaml:"port"`         // PORT, -port
+	MetricsPort int `yaml:"metrics_port"` // METRICS_PORT, -metrics-port
+	// GatewayPort serves the Simple service as HTTP/JSON, with the TLS
+	// settings of gRPC; 0 turns it off.
+	GatewayPort int `yaml:"gateway_port"` // GATEWAY_PORT, -gateway-port
+	// SinglePort serves everything on Port, routing each request by content
+	// type and path: gRPC, gRPC-Web and Connect, /metrics and /admin, and the
+	// HTTP/JSON gateway for the rest. MetricsPort is then not listened on.
+	SinglePort bool `yaml:"single_port"` // SINGLE_PORT, -single-port
+	// SinglePortAdmin lets POST and PUT /admin/health change serving statuses
+	// on the single port, which anyone who can call the service reaches too.
+	// Without it only GET is served there.
+	SinglePortAdmin bool   `yaml:"single_port_admin"` // SINGLE_PORT_ADMIN, -single-port-admin
+	ProjectID       string `yaml:"project_id"`        // GOOGLE_CLOUD_PROJECT, -project
+	// Domain names the tracer of the Simple service and must be set.
+	Domain string `yaml:"domain"` // DOMAIN, -domain
+	// Sleep is the wait between ListMessage messages when the request has no interval.
+	Sleep          time.Duration `yaml:"sleep"`           // SLEEP (seconds), -sleep
+	FaultInjection bool          `yaml:"fault_injection"` // FAULT_INJECTION, -fault-injection
+	// DrainTimeout is how long in-flight RPCs may run after SIGTERM.
+	DrainTimeout time.Duration `yaml:"drain_timeout"` // DRAIN_TIMEOUT, -drain-timeout
+	// DebugErrors sends the underlying error and stack of failures to clients
+	// as google.rpc.DebugInfo. Keep it off where clients are not trusted.
+	DebugErrors bool `yaml:"debug_errors"` // DEBUG_ERRORS, -debug-errors
+
+	Debug      Debug      `yaml:"debug"`
+	GRPC       GRPC       `yaml:"grpc"`
+	TLS        TLS        `yaml:"tls"`
+	Web        Web        `yaml:"web"`
+	Auth       Auth       `yaml:"auth"`
+	Limits     Limits     `yaml:"limits"`
+	Validation Validation `yaml:"validation"`
+	Store      Store      `yaml:"store"`
+	IDs        IDs        `yaml:"ids"`
+	Log        Log        `yaml:"log"`
+	Trace      Trace      `yaml:"trace"`
+	Metrics    Metrics    `yaml:"metrics"`
+}
+
+// Debug turns on ways to look into a running server, none of them for
+// untrusted networks. Admin registers the gRPC admin services on the gRPC
+// port, which is channelz here: CSDS only reports xDS configuration and the
+// server does not use xDS. Pprof serves net/http/pprof under /debug/pprof/ and
+// Config serves the configuration in effect, secrets redacted, as YAML at
+// /debug/config, both next to /metrics.
+type Debug struct {
+	Admin  bool `yaml:"admin"`  // DEBUG_ADMIN, -debug-admin
+	Pprof  bool `yaml:"pprof"`  // DEBUG_PPROF, -debug-pprof
+	Config bool `yaml:"config"` // DEBUG_CONFIG, -debug-config
+}
+
+// GRPC tunes the connections of the gRPC server; zero values keep the gRPC
+// defaults.
+//
+// The server pings clients idle for KeepaliveTime and closes the connection
+// when no answer comes in KeepaliveTimeout. Clients pinging more often than
+// every KeepaliveMinTime, or at all without calls when
+// KeepalivePermitWithoutStream is off, are sent GOAWAY. Connections are sent
+// GOAWAY after MaxConnectionIdle without calls, or once they are
+// MaxConnectionAge old, the calls on them then getting MaxConnectionAgeGrace
+// to finish. Messages over MaxRecvMsgSize or MaxSendMsgSize bytes fail with
+// ResourceExhausted.
+//
+// The HTTP front ends call the server in-process, but for gRPC calls on the
+// main port in single-port mode or with Web on, which the HTTP server there
+// hands to the gRPC server. The clients of the front ends, those gRPC ones
+// included, get MaxConcurrentStreams and MaxConnectionIdle on each of their
+// connections, gateway clients neither, and all of them the message sizes.
+type GRPC struct {
+	KeepaliveTime                time.Duration `yaml:"keepalive_time"`                  // GRPC_KEEPALIVE_TIME, -grpc-keepalive-time
+	KeepaliveTimeout             time.Duration `yaml:"keepalive_timeout"`               // GRPC_KEEPALIVE_TIMEOUT, -grpc-keepalive-timeout
+	KeepaliveMinTime             time.Duration `yaml:"keepalive_min_time"`              // GRPC_KEEPALIVE_MIN_TIME, -grpc-keepalive-min-time
+	KeepalivePermitWithoutStream bool          `yaml:"keepalive_permit_without_stream"` // GRPC_KEEPALIVE_PERMIT_WITHOUT_STREAM, -grpc-keepalive-permit-without-stream
+	MaxConnectionIdle            time.Duration `yaml:"max_connection_idle"`             // GRPC_MAX_CONNECTION_IDLE, -grpc-max-connection-idle
+	MaxConnectionAge             time.Duration `yaml:"max_connection_age"`              // GRPC_MAX_CONNECTION_AGE, -grpc-max-connection-age
+	MaxConnectionAgeGrace        time.Duration `yaml:"max_connection_age_grace"`        // GRPC_MAX_CONNECTION_AGE_GRACE, -grpc-max-connection-age-grace
+	MaxConcurrentStreams         int           `yaml:"max_concurrent_streams"`          // GRPC_MAX_CONCURRENT_STREAMS, -grpc-max-concurrent-streams
+	MaxRecvMsgSize               int           `yaml:"max_recv_msg_size"`               // GRPC_MAX_RECV_MSG_SIZE, -grpc-max-recv-msg-size
+	MaxSendMsgSize               int           `yaml:"max_send_msg_size"`               // GRPC_MAX_SEND_MSG_SIZE, -grpc-max-send-msg-size
+}
+
+// TLS turns on TLS for gRPC when CertFile and KeyFile are set, and mutual TLS
+// when ClientCAFile is set too. The files are read again when they change.
+type TLS struct {
+	CertFile     string `yaml:"cert_file"`              // TLS_CERT_FILE, -tls-cert-file
+	KeyFile      string `yaml:"key_file" secret:"true"` // TLS_KEY_FILE, -tls-key-file
+	ClientCAFile string `yaml:"client_ca_file"`         // TLS_CLIENT_CA_FILE, -tls-client-ca-file
+	// ClientAuth is request (verify a client certificate when one is sent)
+	// or require, the default with ClientCAFile.
+	ClientAuth string `yaml:"client_auth"` // TLS_CLIENT_AUTH, -tls-client-auth
+}
+
+// Web serves gRPC-Web, in binary and text form, and the Connect protocol on
+// the main port next to gRPC, over HTTP/1.1 and HTTP/2 with or without TLS, so
+// browsers and curl can call the Simple service. CORSOrigins lists the origins of the pages allowed
+// to call it from browsers, "*" for any.
+type Web struct {
+	Enabled     bool          `yaml:"enabled"`      // WEB, -web
+	CORSOrigins []string      `yaml:"cors_origins"` // WEB_CORS_ORIGINS (comma separated), -web-cors-origins
+	CORSMaxAge  time.Duration `yaml:"cors_max_age"` // WEB_CORS_MAX_AGE, -web-cors-max-age
+}
+
+// Auth turns on authentication when JWKSFile, HMACKeyFile or APIKeysFile is
+// set. Callers send a JWT as "authorization: Bearer <token>" or an API key as
+// "x-api-key: <key>".
+//
+// Policy entries are Method=rule, where Method is a Simple method name
+// ("PingPong"), a full one ("/simple.Simple/PingPong") or a service
+// ("/grpc.reflection.v1alpha.ServerReflection/"), and rule is public,
+// authenticated or space separated scope:<name> entries that must all be
+// granted. Methods without an entry get DefaultPolicy. Health checks are
+// always public.
+type Auth struct {
+	JWKSFile    string `yaml:"jwks_file"`                   // AUTH_JWKS_FILE, -auth-jwks-file
+	HMACKeyFile string `yaml:"hmac_key_file" secret:"true"` // AUTH_HMAC_KEY_FILE, -auth-hmac-key-file
+	Issuer      string `yaml:"issuer"`                      // AUTH_ISSUER, -auth-issuer
+	Audience    string `yaml:"audience"`                    // AUTH_AUDIENCE, -auth-audience
+	// APIKeysFile is a YAML or JSON list of name, key and scopes.
+	APIKeysFile   string   `yaml:"api_keys_file" secret:"true"` // AUTH_API_KEYS_FILE, -auth-api-keys-file
+	Policy        []string `yaml:"policy"`                      // AUTH_POLICY (comma separated), -auth-policy
+	DefaultPolicy string   `yaml:"default_policy"`              // AUTH_DEFAULT_POLICY, -auth-default-policy
+}
+
+// Enabled reports whether any credentials are configured.
+func (a Auth) Enabled() bool {
+	return a.JWKSFile != "" || a.HMACKeyFile != "" || a.APIKeysFile != ""
+}
+
+// Limits protect the server from clients sending too much. Clients are told
+// apart by Key: peer (their address), principal (the authenticated caller,
+// see Auth) or header:<name> (a metadata value); the last two fall back to the
+// address. Limits apply before authentication, so unauthenticated calls count
+// too, except with principal. Zero values turn a limit off, and health checks
+// are never limited.
+//
+// Rate is the calls per second each client may make to each method, with
+// bursts of up to Burst calls. Methods overrides it with Method=limit[/burst]
+// entries, methods named as in Auth.Policy.
+type Limits struct {
+	Rate    float64  `yaml:"rate"`    // LIMIT_RATE, -limit-rate
+	Burst   int      `yaml:"burst"`   // LIMIT_BURST, -limit-burst
+	Methods []string `yaml:"methods"` // LIMIT_METHODS (comma separated), -limit-methods
+	Key     string   `yaml:"key"`     // LIMIT_KEY, -limit-key
+	// MaxStreams is how many streaming calls a client may have open at once.
+	MaxStreams int `yaml:"max_streams"` // LIMIT_MAX_STREAMS, -limit-max-streams
+	// MaxStreamMessages is how many messages one stream may carry each way.
+	MaxStreamMessages int `yaml:"max_stream_messages"` // LIMIT_MAX_STREAM_MESSAGES, -limit-max-stream-messages
+}
+
+// Enabled reports whether any limit is set.
+func (l Limits) Enabled() bool {
+	return l.Rate > 0 || len(l.Methods) > 0 || l.MaxStreams > 0 || l.MaxStreamMessages > 0
+}
+
+// Validation rejects requests breaking the field rules of proto/validate.proto
+// with InvalidArgument. It is off unless turned on, as it rejects requests
+// earlier versions accepted, such as PutMessage with an empty message. Bounds
+// replace the upper bounds of those rules with Message.field=value entries,
+// such as Request.number=100 or Request.interval=10s.
+type Validation struct {
+	Enabled bool     `yaml:"enabled"` // VALIDATE, -validate
+	Bounds  []string `yaml:"bounds"`  // VALIDATE_BOUNDS (comma separated), -validate-bounds
+}
+
+type Store struct {
+	Type string `yaml:"type"` // STORE, -store: memory or bolt
+	Path string `yaml:"path"` // STORE_PATH, -store-path
+}
+
+type IDs struct {
+	Mode string `yaml:"mode"` // ID_MODE, -id-mode: counter or random
+	Node int    `yaml:"node"` // ID_NODE, -id-node: 0 to 127
+	// StatePath defaults to Store.Path + ".id" with the bolt store.
+	StatePath string `yaml:"state_path"` // ID_STATE_PATH, -id-state-path
+}
+
+// Log is passed on to logging.New.
+type Log struct {
+	Level   string `yaml:"level"`   // LOG_LEVEL, -log-level
+	Format  string `yaml:"format"`  // LOG_FORMAT, -log-format
+	Trace   string `yaml:"trace"`   // LOG_TRACE_FORMAT, -log-trace-format
+	Payload string `yaml:"payload"` // LOG_PAYLOAD, -log-payload
+}
+
+// Trace selects where spans go and which of them are kept.
+//
+// Exporter is one of cloudtrace, otlp-grpc, otlp-http, stdout, json or none,
+// and defaults to cloudtrace when ProjectID is set. The OTLP exporters read
+// their endpoint and headers from the standard OTEL_EXPORTER_OTLP_* variables.
+//
+// Sampler follows OTEL_TRACES_SAMPLER: always_on, always_off, traceidratio,
+// parentbased_always_on, parentbased_always_off or parentbased_traceidratio,
+// with the ratio in SamplerArg.
+type Trace struct {
+	Exporter string `yaml:"exporter"` // TRACE_EXPORTER, -trace-exporter
+	// ServiceName falls back to K_SERVICE, then "sample".
+	ServiceName string `yaml:"service_name"` // OTEL_SERVICE_NAME, -service-name
+	Sampler     string `yaml:"sampler"`      // OTEL_TRACES_SAMPLER, -trace-sampler
+	SamplerArg  string `yaml:"sampler_arg"`  // OTEL_TRACES_SAMPLER_ARG, -trace-sampler-arg
+}
+
+// Metrics lists where metrics go: any of prometheus, otlp-grpc and otlp-http,
+// or none. prometheus adds the OpenTelemetry metrics to the /metrics endpoint
+// next to the go-grpc-prometheus ones; the OTLP exporters push every Interval.
+type Metrics struct {
+	Exporters []string      `yaml:"exporters"` // METRICS_EXPORTER (comma separated), -metrics-exporter
+	Interval  time.Duration `yaml:"interval"`  // METRICS_INTERVAL, -metrics-interval
+}
+
+// Default returns the configuration used when nothing else is set.
+func Default() Config {
+	return Config{
+		Port:         8080,
+		MetricsPort:  18080,
+		DrainTimeout: 5 * time.Second,
+		Auth:         Auth{DefaultPolicy: "authenticated"},
+		Limits:       Limits{Key: "peer"},
+		Web:          Web{CORSMaxAge: 10 * time.Minute},
+		Store:        Store{Type: "memory", Path: "simple.db"},
+		IDs:          IDs{Mode: "counter"},
+		Log:          Log{Level: "debug", Format: "json", Trace: "cloud", Payload: "on"},
+		Trace:        Trace{Sampler: "always_on"},
+		Metrics:      Metrics{Exporters: []string{"prometheus"}, Interval: time.Minute},
+	}
+}
+
+// setting ties a Config field to its environment variable and flag.
+type setting struct {
+	env, flag, usage string
+	set              func(c *Config, v string) error
+}
+
+var settings = []setting{
+	{"PORT", "port", "port to serve gRPC on", setInt(func(c *Config) *int { return &c.Port })},
+	{"METRICS_PORT", "metrics-port", "port to serve /metrics and /admin on", setInt(func(c *Config) *int { return &c.MetricsPort })},
+	{"GATEWAY_PORT", "gateway-port", "port to serve the HTTP/JSON gateway on, 0 for none", setInt(func(c *Config) *int { return &c.GatewayPort })},
+	{"SINGLE_PORT", "single-port", "serve gRPC, /metrics, /admin and the gateway all on port", setBool(func(c *Config) *bool { return &c.SinglePort })},
+	{"SINGLE_PORT_ADMIN", "single-port-admin", "allow changing health statuses through /admin/health on the single port", setBool(func(c *Config) *bool { return &c.SinglePortAdmin })},
+	{"GOOGLE_CLOUD_PROJECT", "project", "Google Cloud project", setString(func(c *Config) *string { return &c.ProjectID })},
+	{"DOMAIN", "domain", "name of the service tracer (required)", setString(func(c *Config) *string { return &c.Domain })},
+	{"SLEEP", "sleep", "seconds between ListMessage messages", setSeconds(func(c *Config) *time.Duration { return &c.Sleep })},
+	{"FAULT_INJECTION", "fault-injection", "inject faults requested by x-fault-* metadata", setBool(func(c *Config) *bool { return &c.FaultInjection })},
+	{"DRAIN_TIMEOUT", "drain-timeout", "how long in-flight RPCs may run after SIGTERM", setDuration(func(c *Config) *time.Duration { return &c.DrainTimeout })},
+	{"DEBUG_ERRORS", "debug-errors", "send the underlying error of failures to clients as DebugInfo", setBool(func(c *Config) *bool { return &c.DebugErrors })},
+	{"DEBUG_ADMIN", "debug-admin", "register the gRPC admin services (channelz)", setBool(func(c *Config) *bool { return &c.Debug.Admin })},
+	{"DEBUG_PPROF", "debug-pprof", "serve /debug/pprof/ next to /metrics", setBool(func(c *Config) *bool { return &c.Debug.Pprof })},
+	{"DEBUG_CONFIG", "debug-config", "serve the configuration in effect at /debug/config next to /metrics", setBool(func(c *Config) *bool { return &c.Debug.Config })},
+	{"GRPC_KEEPALIVE_TIME", "grpc-keepalive-time", "ping clients idle for this long", setDuration(func(c *Config) *time.Duration { return &c.GRPC.KeepaliveTime })},
+	{"GRPC_KEEPALIVE_TIMEOUT", "grpc-keepalive-timeout", "close connections not answering a ping within this", setDuration(func(c *Config) *time.Duration { return &c.GRPC.KeepaliveTimeout })},
+	{"GRPC_KEEPALIVE_MIN_TIME", "grpc-keepalive-min-time", "least time between client pings before GOAWAY", setDuration(func(c *Config) *time.Duration { return &c.GRPC.KeepaliveMinTime })},
+	{"GRPC_KEEPALIVE_PERMIT_WITHOUT_STREAM", "grpc-keepalive-permit-without-stream", "allow client pings without calls", setBool(func(c *Config) *bool { return &c.GRPC.KeepalivePermitWithoutStream })},
+	{"GRPC_MAX_CONNECTION_IDLE", "grpc-max-connection-idle", "GOAWAY connections without calls for this long", setDuration(func(c *Config) *time.Duration { return &c.GRPC.MaxConnectionIdle })},
+	{"GRPC_MAX_CONNECTION_AGE", "grpc-max-connection-age", "GOAWAY connections this old", setDuration(func(c *Config) *time.Duration { return &c.GRPC.MaxConnectionAge })},
+	{"GRPC_MAX_CONNECTION_AGE_GRACE", "grpc-max-connection-age-grace", "time calls get to finish after max connection age", setDuration(func(c *Config) *time.Duration { return &c.GRPC.MaxConnectionAgeGrace })},
+	{"GRPC_MAX_CONCURRENT_STREAMS", "grpc-max-concurrent-streams", "concurrent calls per connection", setInt(func(c *Config) *int { return &c.GRPC.MaxConcurrentStreams })},
+	{"GRPC_MAX_RECV_MSG_SIZE", "grpc-max-recv-msg-size", "largest message in bytes the server accepts", setInt(func(c *Config) *int { return &c.GRPC.MaxRecvMsgSize })},
+	{"GRPC_MAX_SEND_MSG_SIZE", "grpc-max-send-msg-size", "largest message in bytes the server sends", setInt(func(c *Config) *int { return &c.GRPC.MaxSendMsgSize })},
+	{"TLS_CERT_FILE", "tls-cert-file", "PEM certificate to serve gRPC over TLS with", setString(func(c *Config) *string { return &c.TLS.CertFile })},
+	{"TLS_KEY_FILE", "tls-key-file", "PEM key of the TLS certificate", setString(func(c *Config) *string { return &c.TLS.KeyFile })},
+	{"TLS_CLIENT_CA_FILE", "tls-client-ca-file", "PEM CA that client certificates must be signed by", setString(func(c *Config) *string { return &c.TLS.ClientCAFile })},
+	{"TLS_CLIENT_AUTH", "tls-client-auth", "client certificates: request or require", setString(func(c *Config) *string { return &c.TLS.ClientAuth })},
+	{"WEB", "web", "serve gRPC-Web and Connect on the main port", setBool(func(c *Config) *bool { return &c.Web.Enabled })},
+	{"WEB_CORS_ORIGINS", "web-cors-origins", "comma separated origins browsers may call from, * for any", setList(func(c *Config) *[]string { return &c.Web.CORSOrigins })},
+	{"WEB_CORS_MAX_AGE", "web-cors-max-age", "how long browsers may cache CORS preflight responses", setDuration(func(c *Config) *time.Duration { return &c.Web.CORSMaxAge })},
+	{"AUTH_JWKS_FILE", "auth-jwks-file", "JWKS file with the public keys of bearer tokens", setString(func(c *Config) *string { return &c.Auth.JWKSFile })},
+	{"AUTH_HMAC_KEY_FILE", "auth-hmac-key-file", "file with the shared secret of HS256 bearer tokens", setString(func(c *Config) *string { return &c.Auth.HMACKeyFile })},
+	{"AUTH_ISSUER", "auth-issuer", "required iss claim of bearer tokens", setString(func(c *Config) *string { return &c.Auth.Issuer })},
+	{"AUTH_AUDIENCE", "auth-audience", "required aud claim of bearer tokens", setString(func(c *Config) *string { return &c.Auth.Audience })},
+	{"AUTH_API_KEYS_FILE", "auth-api-keys-file", "YAML or JSON list of API keys", setString(func(c *Config) *string { return &c.Auth.APIKeysFile })},
+	{"AUTH_POLICY", "auth-policy", "comma separated Method=rule, rule being public, authenticated or scope:<name>", setList(func(c *Config) *[]string { return &c.Auth.Policy })},
+	{"AUTH_DEFAULT_POLICY", "auth-default-policy", "rule for methods not in the policy", setString(func(c *Config) *string { return &c.Auth.DefaultPolicy })},
+	{"LIMIT_RATE", "limit-rate", "calls per second per client and method, 0 for no limit", setFloat(func(c *Config) *float64 { return &c.Limits.Rate })},
+	{"LIMIT_BURST", "limit-burst", "calls at once per client and method", setInt(func(c *Config) *int { return &c.Limits.Burst })},
+	{"LIMIT_METHODS", "limit-methods", "comma separated Method=limit[/burst] overriding the rate", setList(func(c *Config) *[]string { return &c.Limits.Methods })},
+	{"LIMIT_KEY", "limit-key", "what tells clients apart: peer, principal or header:<name>", setString(func(c *Config) *string { return &c.Limits.Key })},
+	{"LIMIT_MAX_STREAMS", "limit-max-streams", "concurrent streams per client", setInt(func(c *Config) *int { return &c.Limits.MaxStreams })},
+	{"LIMIT_MAX_STREAM_MESSAGES", "limit-max-stream-messages", "messages per stream each way", setInt(func(c *Config) *int { return &c.Limits.MaxStreamMessages })},
+	{"VALIDATE", "validate", "reject requests breaking the rules in proto/validate.proto", setBool(func(c *Config) *bool { return &c.Validation.Enabled })},
+	{"VALIDATE_BOUNDS", "validate-bounds", "comma separated Message.field=value replacing upper bounds", setList(func(c *Config) *[]string { return &c.Validation.Bounds })},
+	{"STORE", "store", "message store: memory or bolt", setString(func(c *Config) *string { return &c.Store.Type })},
+	{"STORE_PATH", "store-path", "bolt database file", setString(func(c *Config) *string { return &c.Store.Path })},
+	{"ID_MODE", "id-mode", "id allocation: counter or random", setString(func(c *Config) *string { return &c.IDs.Mode })},
+	{"ID_NODE", "id-node", "node number put in the upper bits of counter ids", setInt(func(c *Config) *int { return &c.IDs.Node })},
+	{"ID_STATE_PATH", "id-state-path", "file keeping the id counter across restarts", setString(func(c *Config) *string { return &c.IDs.StatePath })},
+	{"LOG_LEVEL", "log-level", "trace, debug, info, warn or error", setString(func(c *Config) *string { return &c.Log.Level })},
+	{"LOG_FORMAT", "log-format", "json or console", setString(func(c *Config) *string { return &c.Log.Format })},
+	{"LOG_TRACE_FORMAT", "log-trace-format", "trace fields in logs: cloud or otel", setString(func(c *Config) *string { return &c.Log.Trace })},
+	{"LOG_PAYLOAD", "log-payload", "payload logging: on, off or redact", setString(func(c *Config) *string { return &c.Log.Payload })},
+	{"TRACE_EXPORTER", "trace-exporter", "cloudtrace, otlp-grpc, otlp-http, stdout, json or none", setString(func(c *Config) *string { return &c.Trace.Exporter })},
+	{"OTEL_SERVICE_NAME", "service-name", "service name in traces and metrics", setString(func(c *Config) *string { return &c.Trace.ServiceName })},
+	{"OTEL_TRACES_SAMPLER", "trace-sampler", "trace sampler", setString(func(c *Config) *string { return &c.Trace.Sampler })},
+	{"OTEL_TRACES_SAMPLER_ARG", "trace-sampler-arg", "ratio for the traceidratio samplers", setString(func(c *Config) *string { return &c.Trace.SamplerArg })},
+	{"METRICS_EXPORTER", "metrics-exporter", "comma separated: prometheus, otlp-grpc, otlp-http or none", setList(func(c *Config) *[]string { return &c.Metrics.Exporters })},
+	{"METRICS_INTERVAL", "metrics-interval", "how often OTLP metrics are pushed", setDuration(func(c *Config) *time.Duration { return &c.Metrics.Interval })},
+}
+
+// Load builds the configuration from args (without the program name),
+// getenv and the optional config file, and validates it.
+func Load(args []string, getenv func(string) string) (Config, error) {
+	fs := flag.NewFlagSet("simple", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	file := fs.String("config", getenv("CONFIG_FILE"), "YAML or JSON config file")
+	flags := map[string]string{}
+	for _, s := range settings {
+		name := s.flag
+		fs.Func(name, s.usage, func(v string) error {
+			flags[name] = v
+			return nil
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	c := Default()
+	if *file != "" {
+		if err := c.readFile(*file); err != nil {
+			return Config{}, err
+		}
+	}
+
+	var errs Errors
+	for _, s := range settings {
+		if v := getenv(s.env); v != "" {
+			if err := s.set(&c, v); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", s.env, err))
+			}
+		}
+	}
+	for _, s := range settings {
+		if v, ok := flags[s.flag]; ok {
+			if err := s.set(&c, v); err != nil {
+				errs = append(errs, fmt.Errorf("-%s: %w", s.flag, err))
+			}
+		}
+	}
+
+	if c.IDs.StatePath == "" && c.Store.Type == "bolt" {
+		c.IDs.StatePath = c.Store.Path + ".id"
+	}
+	if c.Trace.Exporter == "" {
+		c.Trace.Exporter = "none"
+		if c.ProjectID != "" {
+			c.Trace.Exporter = "cloudtrace"
+		}
+	}
+	if c.Trace.ServiceName == "" {
+		c.Trace.ServiceName = getenv("K_SERVICE")
+	}
+	if c.Trace.ServiceName == "" {
+		c.Trace.ServiceName = "sample"
+	}
+
+	errs = append(errs, c.Validate()...)
+	if len(errs) > 0 {
+		return c, errs
+	}
+	return c, nil
+}
+
+func (c *Config) readFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && err != io.EOF {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Usage lists the flags and environment variables Load reads.
+func Usage(w io.Writer) {
+	fmt.Fprintf(w, "  -config (CONFIG_FILE)\n    \tYAML or JSON config file\n")
+	for _, s := range settings {
+		fmt.Fprintf(w, "  -%s (%s)\n    \t%s\n", s.flag, s.env, s.usage)
+	}
+}
+
+// redactedValue replaces the values of secret fields in Redacted.
+const redactedValue = "[redacted]"
+
+// Redacted returns c with the values of the fields tagged secret replaced,
+// so it can be shown.
+func (c Config) Redacted() Config {
+	redact(reflect.ValueOf(&c).Elem())
+	return c
+}
+
+func redact(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		switch {
+		case f.Kind() == reflect.Struct:
+			redact(f)
+		case v.Type().Field(i).Tag.Get("secret") == "true" && f.Kind() == reflect.String && f.String() != "":
+			f.SetString(redactedValue)
+		}
+	}
+}
+
+// Errors collects every problem found in a configuration.
+type Errors []error
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return "invalid configuration: " + strings.Join(msgs, "; ")
+}
+
+// Validate reports every invalid field of c.
+func (c Config) Validate() Errors {
+	var errs Errors
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	oneOf := func(name, v string, allowed ...string) {
+		for _, a := range allowed {
+			if v == a {
+				return
+			}
+		}
+		errs = append(errs, fmt.Errorf("%s must be one of %s: %q", name, strings.Join(allowed, ", "), v))
+	}
+
+	check(c.Domain != "", "domain is not set")
+	check(c.Port > 0 && c.Port < 65536, "port must be between 1 and 65535: %d", c.Port)
+	check(c.MetricsPort > 0 && c.MetricsPort < 65536, "metrics_port must be between 1 and 65535: %d", c.MetricsPort)
+	check(c.SinglePort || c.Port != c.MetricsPort, "port and metrics_port must differ: %d", c.Port)
+	check(c.GatewayPort >= 0 && c.GatewayPort < 65536, "gateway_port must be between 0 and 65535: %d", c.GatewayPort)
+	check(c.GatewayPort == 0 || c.GatewayPort != c.Port && c.GatewayPort != c.MetricsPort, "gateway_port must differ from port and metrics_port: %d", c.GatewayPort)
+	check(c.Sleep >= 0, "sleep must not be negative: %s", c.Sleep)
+	check(c.DrainTimeout >= 0, "drain_timeout must not be negative: %s", c.DrainTimeout)
+
+	for _, d := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"keepalive_time", c.GRPC.KeepaliveTime},
+		{"keepalive_timeout", c.GRPC.KeepaliveTimeout},
+		{"keepalive_min_time", c.GRPC.KeepaliveMinTime},
+		{"max_connection_idle", c.GRPC.MaxConnectionIdle},
+		{"max_connection_age", c.GRPC.MaxConnectionAge},
+		{"max_connection_age_grace", c.GRPC.MaxConnectionAgeGrace},
+	} {
+		check(d.value >= 0, "grpc.%s must not be negative: %s", d.name, d.value)
+	}
+	check(c.GRPC.MaxConcurrentStreams >= 0 && int64(c.GRPC.MaxConcurrentStreams) <= math.MaxUint32, "grpc.max_concurrent_streams must be between 0 and %d: %d", uint32(math.MaxUint32), c.GRPC.MaxConcurrentStreams)
+	check(c.GRPC.MaxRecvMsgSize >= 0, "grpc.max_recv_msg_size must not be negative: %d", c.GRPC.MaxRecvMsgSize)
+	check(c.GRPC.MaxSendMsgSize >= 0, "grpc.max_send_msg_size must not be negative: %d", c.GRPC.MaxSendMsgSize)
+
+	check(c.Web.Enabled || len(c.Web.CORSOrigins) == 0, "web.cors_origins needs web.enabled")
+	check(c.Web.CORSMaxAge >= 0, "web.cors_max_age must not be negative: %s", c.Web.CORSMaxAge)
+
+	check((c.TLS.CertFile == "") == (c.TLS.KeyFile == ""), "tls.cert_file and tls.key_file must be set together")
+	check(c.TLS.ClientCAFile == "" || c.TLS.CertFile != "", "tls.client_ca_file needs tls.cert_file")
+	if c.TLS.ClientAuth != "" {
+		oneOf("tls.client_auth", c.TLS.ClientAuth, "request", "require")
+		check(c.TLS.ClientCAFile != "", "tls.client_auth needs tls.client_ca_file")
+	}
+
+	check(c.Auth.Enabled() || c.Auth.Issuer == "" && c.Auth.Audience == "" && len(c.Auth.Policy) == 0,
+		"auth needs auth.jwks_file, auth.hmac_key_file or auth.api_keys_file")
+	for _, p := range c.Auth.Policy {
+		check(strings.Contains(p, "="), "auth.policy entries must be Method=rule: %q", p)
+	}
+
+	check(c.Limits.Rate >= 0, "limits.rate must not be negative: %g", c.Limits.Rate)
+	check(c.Limits.Burst >= 0, "limits.burst must not be negative: %d", c.Limits.Burst)
+	check(c.Limits.MaxStreams >= 0, "limits.max_streams must not be negative: %d", c.Limits.MaxStreams)
+	check(c.Limits.MaxStreamMessages >= 0, "limits.max_stream_messages must not be negative: %d", c.Limits.MaxStreamMessages)
+	for _, m := range c.Limits.Methods {
+		check(strings.Contains(m, "="), "limits.methods entries must be Method=limit[/burst]: %q", m)
+	}
+	if !strings.HasPrefix(c.Limits.Key, "header:") {
+		oneOf("limits.key", c.Limits.Key, "peer", "principal", "header:<name>")
+	}
+
+	for _, b := range c.Validation.Bounds {
+		check(strings.Contains(b, "="), "validation.bounds entries must be Message.field=value: %q", b)
+	}
+
+	oneOf("store.type", c.Store.Type, "memory", "bolt")
+	check(c.Store.Type != "bolt" || c.Store.Path != "", "store.path is required with the bolt store")
+	oneOf("ids.mode", c.IDs.Mode, "counter", "random")
+	check(c.IDs.Node >= 0 && c.IDs.Node <= 127, "ids.node must be between 0 and 127: %d", c.IDs.Node)
+
+	oneOf("log.level", c.Log.Level, "trace", "debug", "info", "warn", "error")
+	oneOf("log.format", c.Log.Format, "json", "console")
+	oneOf("log.trace", c.Log.Trace, "cloud", "otel")
+	oneOf("log.payload", c.Log.Payload, "on", "off", "redact")
+
+	oneOf("trace.exporter", c.Trace.Exporter, "cloudtrace", "otlp-grpc", "otlp-http", "stdout", "json", "none")
+	oneOf("trace.sampler", c.Trace.Sampler, "always_on", "always_off", "traceidratio",
+		"parentbased_always_on", "parentbased_always_off", "parentbased_traceidratio")
+	if c.Trace.SamplerArg != "" {
+		r, err := strconv.ParseFloat(c.Trace.SamplerArg, 64)
+		check(err == nil && r >= 0 && r <= 1, "trace.sampler_arg must be between 0 and 1: %s", c.Trace.SamplerArg)
+	}
+
+	for _, e := range c.Metrics.Exporters {
+		oneOf("metrics.exporters", e, "prometheus", "otlp-grpc", "otlp-http", "none")
+	}
+	check(c.Metrics.Interval > 0, "metrics.interval must be positive: %s", c.Metrics.Interval)
+	return errs
+}
+
+func setString(field func(*Config) *string) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		*field(c) = v
+		return nil
+	}
+}
+
+func setInt(field func(*Config) *int) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("not a number: %q", v)
+		}
+		*field(c) = i
+		return nil
+	}
+}
+
+func setFloat(field func(*Config) *float64) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("not a number: %q", v)
+		}
+		*field(c) = f
+		return nil
+	}
+}
+
+func setBool(field func(*Config) *bool) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("not a boolean: %q", v)
+		}
+		*field(c) = b
+		return nil
+	}
+}
+
+func setDuration(field func(*Config) *time.Duration) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("not a duration: %q", v)
+		}
+		*field(c) = d
+		return nil
+	}
+}
+
+// setSeconds accepts a plain number of seconds, as SLEEP always has, or a duration.
+func setSeconds(field func(*Config) *time.Duration) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		if s, err := strconv.Atoi(v); err == nil {
+			*field(c) = time.Duration(s) * time.Second
+			return nil
+		}
+		return setDuration(field)(c, v)
+	}
+}
+
+func setList(field func(*Config) *[]string) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		var list []string
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				list = append(list, s)
+			}
+		}
+		*field(c) = list
+		return nil
+	}
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func env(m map[string]string) func(string) string {
+	return func(k string) string { return m[k] }
+}
+
+func writeFile(t *testing.T, name, data string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	c, err := Load(nil, env(map[string]string{"DOMAIN": "example.com"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Default()
+	want.Domain = "example.com"
+	want.Trace.Exporter = "none"
+	want.Trace.ServiceName = "sample"
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("got %+v, want %+v", c, want)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := writeFile(t, "simple.yaml", `
+domain: file
+port: 9000
+metrics_port: 9001
+sleep: 2s
+store:
+  type: bolt
+  path: /tmp/file.db
+metrics:
+  exporters: [otlp-grpc]
+`)
+	c, err := Load(
+		[]string{"-config", file, "-port", "9100"},
+		env(map[string]string{"PORT": "9050", "SLEEP": "3", "GOOGLE_CLOUD_PROJECT": "p", "K_SERVICE": "svc"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Domain != "file" || c.MetricsPort != 9001 {
+		t.Errorf("file not applied: %+v", c)
+	}
+	if c.Port != 9100 {
+		t.Errorf("flag should win over env and file: %d", c.Port)
+	}
+	if c.Sleep != 3*time.Second {
+		t.Errorf("env should win over file: %s", c.Sleep)
+	}
+	if c.IDs.StatePath != "/tmp/file.db.id" {
+		t.Errorf("state path not derived from store path: %q", c.IDs.StatePath)
+	}
+	if c.Trace.Exporter != "cloudtrace" || c.Trace.ServiceName != "svc" {
+		t.Errorf("trace defaults not derived: %+v", c.Trace)
+	}
+	if !reflect.DeepEqual(c.Metrics.Exporters, []string{"otlp-grpc"}) {
+		t.Errorf("got exporters %v", c.Metrics.Exporters)
+	}
+}
+
+func TestLoadJSON(t *testing.T) {
+	file := writeFile(t, "simple.json", `{"domain": "json", "drain_timeout": "1s", "log": {"format": "console"}}`)
+	c, err := Load(nil, env(map[string]string{"CONFIG_FILE": file}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Domain != "json" || c.DrainTimeout != time.Second || c.Log.Format != "console" {
+		t.Errorf("got %+v", c)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	_, err := Load(
+		[]string{"-port", "http"},
+		env(map[string]string{"STORE": "redis", "LOG_LEVEL": "loud", "OTEL_TRACES_SAMPLER_ARG": "2", "DRAIN_TIMEOUT": "soon", "AUTH_POLICY": "PingPong", "WEB_CORS_ORIGINS": "*", "GRPC_MAX_CONNECTION_AGE": "-1s", "ID_NODE": "one"}),
+	)
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected Errors, got %v", err)
+	}
+	for _, want := range []string{"-port", "DRAIN_TIMEOUT", "domain is not set", "store.type", "log.level", "trace.sampler_arg", "auth needs", "auth.policy", "web.cors_origins", "grpc.max_connection_age", "ID_NODE"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("%q is missing from %v", want, err)
+		}
+	}
+
+	file := writeFile(t, "simple.yaml", "domain: x\nprot: 80\n")
+	if _, err := Load([]string{"-config", file}, env(nil)); err == nil {
+		t.Error("expected an error for an unknown key")
+	}
+	if _, err := Load(nil, env(map[string]string{"DOMAIN": "x", "ID_NODE": "128"})); err == nil || !strings.Contains(err.Error(), "ids.node") {
+		t.Errorf("expected an error for an out of range node, got %v", err)
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	c := Default()
+	c.TLS.CertFile, c.TLS.KeyFile = "cert.pem", "key.pem"
+	c.Auth.HMACKeyFile = "hmac.key"
+
+	r := c.Redacted()
+	if r.TLS.KeyFile != redactedValue || r.Auth.HMACKeyFile != redactedValue {
+		t.Errorf("secrets kept: %+v %+v", r.TLS, r.Auth)
+	}
+	if r.TLS.CertFile != "cert.pem" || r.Auth.APIKeysFile != "" || r.Port != c.Port {
+		t.Errorf("other fields changed: %+v %+v", r.TLS, r.Auth)
+	}
+	if c.TLS.KeyFile != "key.pem" {
+		t.Error("c itself was redacted")
+	}
+}
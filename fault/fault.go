@@ -0,0 +1,262 @@
+package fault
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/shin5ok/proto-grpc-simple/rpcerror"
+)
+
+// Faults are requested per call with metadata:
+//
+//	x-fault-code:             status code to return, by name ("UNAVAILABLE") or number ("14")
+//	x-fault-delay:            how long to wait before handling the call, e.g. "2s"
+//	x-fault-percent:          probability in percent that the fault fires (default 100)
+//	x-fault-after-n-messages: on streams, abort once this many messages were sent,
+//	                          or received when only the client streams; unary
+//	                          calls carrying it fail with InvalidArgument
+const (
+	CodeKey    = "x-fault-code"
+	DelayKey   = "x-fault-delay"
+	PercentKey = "x-fault-percent"
+	AfterNKey  = "x-fault-after-n-messages"
+)
+
+// newInjected creates the counter of injected faults from meter. Its error is
+// dropped because the SDK still returns a usable instrument along with it.
+func newInjected(meter metric.Meter) metric.Int64Counter {
+	injected, _ := meter.Int64Counter("simple.fault.injected",
+		metric.WithDescription("Faults injected into RPCs."))
+	return injected
+}
+
+func record(ctx context.Context, injected metric.Int64Counter, method string, kind string) {
+	injected.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("rpc.method", method),
+		attribute.String("fault", kind),
+	))
+}
+
+type Fault struct {
+	Code    codes.Code
+	Delay   time.Duration
+	Percent float64
+	AfterN  int
+}
+
+// FromContext reads the fault requested by the incoming metadata, or nil when there is none.
+func FromContext(ctx context.Context) (*Fault, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+	get := func(key string) string {
+		if v := md.Get(key); len(v) > 0 {
+			return strings.TrimSpace(v[0])
+		}
+		return ""
+	}
+
+	f := &Fault{Percent: 100}
+	found := false
+
+	if v := get(CodeKey); v != "" {
+		c, err := parseCode(v)
+		if err != nil {
+			return nil, rpcerror.New(codes.InvalidArgument, rpcerror.ReasonInvalidMetadata, fmt.Sprintf("invalid %s: %s", CodeKey, v),
+				rpcerror.WithMetadata("key", CodeKey))
+		}
+		f.Code = c
+		found = true
+	}
+	if v := get(DelayKey); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return nil, rpcerror.New(codes.InvalidArgument, rpcerror.ReasonInvalidMetadata, fmt.Sprintf("invalid %s: %s", DelayKey, v),
+				rpcerror.WithMetadata("key", DelayKey))
+		}
+		f.Delay = d
+		found = true
+	}
+	if v := get(PercentKey); v != "" {
+		p, err := strconv.ParseFloat(v, 64)
+		if err != nil || p < 0 || p > 100 {
+			return nil, rpcerror.New(codes.InvalidArgument, rpcerror.ReasonInvalidMetadata, fmt.Sprintf("invalid %s: %s", PercentKey, v),
+				rpcerror.WithMetadata("key", PercentKey))
+		}
+		f.Percent = p
+	}
+	if v := get(AfterNKey); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, rpcerror.New(codes.InvalidArgument, rpcerror.ReasonInvalidMetadata, fmt.Sprintf("invalid %s: %s", AfterNKey, v),
+				rpcerror.WithMetadata("key", AfterNKey))
+		}
+		f.AfterN = n
+		if f.Code == codes.OK {
+			f.Code = codes.Aborted
+		}
+		found = true
+	}
+
+	if !found {
+		return nil, nil
+	}
+	return f, nil
+}
+
+func parseCode(v string) (codes.Code, error) {
+	if i, err := strconv.Atoi(v); err == nil {
+		if i < 0 || i > int(codes.Unauthenticated) {
+			return 0, strconv.ErrRange
+		}
+		return codes.Code(i), nil
+	}
+	var c codes.Code
+	err := c.UnmarshalJSON([]byte(strconv.Quote(strings.ToUpper(v))))
+	return c, err
+}
+
+func (f *Fault) fires() bool {
+	return f.Percent >= 100 || rand.Float64()*100 < f.Percent
+}
+
+func (f *Fault) err() error {
+	return rpcerror.New(f.Code, rpcerror.ReasonFaultInjected, fmt.Sprintf("fault injected: %s", f.Code))
+}
+
+func (f *Fault) wait(ctx context.Context) error {
+	if f.Delay <= 0 {
+		return nil
+	}
+	t := time.NewTimer(f.Delay)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// UnaryServerInterceptor injects faults into unary calls whose full method
+// starts with prefix, counting them with meter.
+func UnaryServerInterceptor(prefix string, meter metric.Meter) grpc.UnaryServerInterceptor {
+	injected := newInjected(meter)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !strings.HasPrefix(info.FullMethod, prefix) {
+			return handler(ctx, req)
+		}
+		f, err := FromContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(metadata.ValueFromIncomingContext(ctx, AfterNKey)) > 0 {
+			return nil, rpcerror.New(codes.InvalidArgument, rpcerror.ReasonInvalidMetadata, fmt.Sprintf("%s only applies to streams", AfterNKey),
+				rpcerror.WithMetadata("key", AfterNKey))
+		}
+		if f == nil || !f.fires() {
+			return handler(ctx, req)
+		}
+		if f.Delay > 0 {
+			record(ctx, injected, info.FullMethod, "delay")
+		}
+		if err := f.wait(ctx); err != nil {
+			return nil, err
+		}
+		if f.Code != codes.OK {
+			record(ctx, injected, info.FullMethod, "code")
+			return nil, f.err()
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor injects faults into streams whose full method starts
+// with prefix, counting them with meter. With x-fault-after-n-messages the
+// stream is aborted mid-way instead of up front.
+func StreamServerInterceptor(prefix string, meter metric.Meter) grpc.StreamServerInterceptor {
+	injected := newInjected(meter)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !strings.HasPrefix(info.FullMethod, prefix) {
+			return handler(srv, ss)
+		}
+		f, err := FromContext(ss.Context())
+		if err != nil {
+			return err
+		}
+		if f == nil || !f.fires() {
+			return handler(srv, ss)
+		}
+		ctx := ss.Context()
+		if f.Delay > 0 {
+			record(ctx, injected, info.FullMethod, "delay")
+		}
+		if err := f.wait(ctx); err != nil {
+			return err
+		}
+		if f.Code == codes.OK {
+			return handler(srv, ss)
+		}
+		if f.AfterN == 0 {
+			record(ctx, injected, info.FullMethod, "code")
+			return f.err()
+		}
+
+		s := &stream{ServerStream: ss, fault: f, countRecv: !info.IsServerStream}
+		err = handler(srv, s)
+		if atomic.LoadInt32(&s.aborted) == 1 {
+			record(ctx, injected, info.FullMethod, "abort")
+			return f.err()
+		}
+		return err
+	}
+}
+
+type stream struct {
+	grpc.ServerStream
+	fault     *Fault
+	countRecv bool
+	count     int32
+	aborted   int32
+}
+
+func (s *stream) next() error {
+	if int(atomic.AddInt32(&s.count, 1)) > s.fault.AfterN {
+		atomic.StoreInt32(&s.aborted, 1)
+		return s.fault.err()
+	}
+	return nil
+}
+
+func (s *stream) SendMsg(m interface{}) error {
+	if s.countRecv {
+		return s.ServerStream.SendMsg(m)
+	}
+	if err := s.next(); err != nil {
+		return err
+	}
+	return s.ServerStream.SendMsg(m)
+}
+
+func (s *stream) RecvMsg(m interface{}) error {
+	if !s.countRecv {
+		return s.ServerStream.RecvMsg(m)
+	}
+	if err := s.next(); err != nil {
+		return err
+	}
+	return s.ServerStream.RecvMsg(m)
+}
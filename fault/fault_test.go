@@ -0,0 +1,156 @@
+package fault
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	pb "github.com/shin5ok/proto-grpc-simple/pb"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type server struct {
+	pb.UnimplementedSimpleServer
+}
+
+func (s *server) PingPong(ctx context.Context, message *pb.Message) (*pb.Message, error) {
+	return &pb.Message{Message: "Pong"}, nil
+}
+
+func (s *server) ListMessage(req *pb.Request, stream pb.Simple_ListMessageServer) error {
+	for n := 0; n < int(req.Number); n++ {
+		if err := stream.Send(&pb.Message{Message: fmt.Sprintf("send %d", n)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func client(t *testing.T) (pb.SimpleClient, sdkmetric.Reader) {
+	l := bufconn.Listen(1024 * 1024)
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test")
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(UnaryServerInterceptor("/simple.Simple/", meter)),
+		grpc.ChainStreamInterceptor(StreamServerInterceptor("/simple.Simple/", meter)),
+	)
+	pb.RegisterSimpleServer(s, &server{})
+	go s.Serve(l)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "localhost", grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+		return l.Dial()
+	}), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewSimpleClient(conn), reader
+}
+
+// injected sums simple.fault.injected by the kind of fault.
+func injected(t *testing.T, reader sdkmetric.Reader) map[string]int64 {
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	counts := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok && m.Name == "simple.fault.injected" {
+				for _, dp := range sum.DataPoints {
+					kind, _ := dp.Attributes.Value("fault")
+					counts[kind.AsString()] += dp.Value
+				}
+			}
+		}
+	}
+	return counts
+}
+
+func TestUnary(t *testing.T) {
+	c, reader := client(t)
+
+	for _, tc := range []struct {
+		name string
+		md   metadata.MD
+		code codes.Code
+	}{
+		{"no fault", metadata.Pairs(), codes.OK},
+		{"by name", metadata.Pairs(CodeKey, "unavailable"), codes.Unavailable},
+		{"by number", metadata.Pairs(CodeKey, "8"), codes.ResourceExhausted},
+		{"never fires", metadata.Pairs(CodeKey, "UNAVAILABLE", PercentKey, "0"), codes.OK},
+		{"invalid", metadata.Pairs(CodeKey, "NO_SUCH_CODE"), codes.InvalidArgument},
+		{"after n messages", metadata.Pairs(AfterNKey, "1"), codes.InvalidArgument},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := metadata.NewOutgoingContext(context.Background(), tc.md)
+			_, err := c.PingPong(ctx, &pb.Message{})
+			if status.Code(err) != tc.code {
+				t.Errorf("expected %s, got %v", tc.code, err)
+			}
+		})
+	}
+	if counts := injected(t, reader); counts["code"] != 2 {
+		t.Errorf("injected: %v", counts)
+	}
+}
+
+func TestDelay(t *testing.T) {
+	c, _ := client(t)
+
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs(DelayKey, "50ms"))
+	start := time.Now()
+	if _, err := c.PingPong(ctx, &pb.Message{}); err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(start) < 50*time.Millisecond {
+		t.Error("call was not delayed")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.PingPong(ctx, &pb.Message{}); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("expected DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestStreamAfterN(t *testing.T) {
+	c, reader := client(t)
+
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs(CodeKey, "UNAVAILABLE", AfterNKey, "3"))
+	stream, err := c.ListMessage(ctx, &pb.Request{Number: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for {
+		_, err := stream.Recv()
+		if err == io.EOF {
+			t.Fatal("stream was not aborted")
+		}
+		if err != nil {
+			if status.Code(err) != codes.Unavailable {
+				t.Errorf("expected Unavailable, got %v", err)
+			}
+			break
+		}
+		n++
+	}
+	if n != 3 {
+		t.Errorf("expected 3 messages before abort, got %d", n)
+	}
+	if counts := injected(t, reader); counts["abort"] != 1 {
+		t.Errorf("injected: %v", counts)
+	}
+}
@@ -0,0 +1,188 @@
+// Package gateway serves the Simple service as HTTP/JSON with the handlers
+// protoc-gen-grpc-gateway generates from the google.api.http options of its
+// proto. Calls go through a gRPC connection, so they pass the same
+// interceptors as any other client.
+//
+// Server streams are written as newline-delimited JSON, one {"result": ...}
+// or {"error": ...} object per message, or as server-sent events when the
+// client accepts text/event-stream. Client streams are read as a sequence of
+// JSON objects, such as newline-delimited JSON.
+package gateway
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/shin5ok/proto-grpc-simple/pb"
+	"github.com/shin5ok/proto-grpc-simple/rpcerror"
+)
+
+// MetadataPrefix marks request headers passed on as gRPC metadata without
+// it, and response headers carrying the gRPC response metadata.
+const MetadataPrefix = runtime.MetadataHeaderPrefix
+
+// ForwardedForKey carries the address of the HTTP client to the server.
+const ForwardedForKey = "x-forwarded-for"
+
+// defaultMaxBody caps request bodies when no other cap is given, as gRPC
+// servers cap messages by default.
+const defaultMaxBody = 4 << 20
+
+// eventStreamType is the Accept value asking for server-sent events.
+const eventStreamType = "text/event-stream"
+
+// Gateway is an http.Handler calling the Simple service on a connection.
+type Gateway struct {
+	mux     *runtime.ServeMux
+	maxBody int64
+}
+
+// New routes the methods of the Simple service to calls on conn. Request
+// bodies over maxBody bytes, 4 MiB when it is 0, fail with
+// ResourceExhausted; this includes client streams, so larger bulk uploads
+// have to go over gRPC.
+func New(conn *grpc.ClientConn, maxBody int) (*Gateway, error) {
+	if maxBody <= 0 {
+		maxBody = defaultMaxBody
+	}
+	g := &Gateway{maxBody: int64(maxBody)}
+	// Unlike the mux's default, fields left unset are left out and unknown
+	// fields rejected, as with the gRPC API.
+	json := &runtime.JSONPb{}
+	g.mux = runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, json),
+		runtime.WithMarshalerOption(eventStreamType, eventStream{json}),
+		runtime.WithIncomingHeaderMatcher(incomingHeader),
+		runtime.WithErrorHandler(g.writeError),
+		runtime.WithRoutingErrorHandler(g.writeRoutingError),
+	)
+	if err := pb.RegisterSimpleHandler(context.Background(), g.mux, conn); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// The gateway is where HTTP clients connect, so an address they claim
+	// to forward for is not taken; the mux forwards their own.
+	r.Header.Del(ForwardedForKey)
+	r.Body = &body{ReadCloser: r.Body, max: g.maxBody}
+	g.mux.ServeHTTP(w, r)
+}
+
+var errBodyTooLarge = errors.New("body too large")
+
+// body fails reads past max bytes and remembers it did, so that the error
+// handler can tell an oversized body from a malformed one.
+type body struct {
+	io.ReadCloser
+	max, n int64
+	over   bool
+}
+
+func (b *body) Read(p []byte) (int, error) {
+	if b.n > b.max {
+		b.over = true
+		return 0, errBodyTooLarge
+	}
+	if rest := b.max + 1 - b.n; int64(len(p)) > rest {
+		p = p[:rest]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+// incomingHeader passes on the headers the server looks at: x-* headers such
+// as x-api-key or x-fault-code, trace context and any header with
+// MetadataPrefix. The mux adds authorization and ForwardedForKey itself.
+func incomingHeader(name string) (string, bool) {
+	key := strings.ToLower(name)
+	switch {
+	case strings.HasPrefix(name, MetadataPrefix):
+		return strings.ToLower(strings.TrimPrefix(name, MetadataPrefix)), true
+	case key == "traceparent", key == "tracestate":
+	case strings.HasPrefix(key, "x-") && key != ForwardedForKey:
+	default:
+		return "", false
+	}
+	return key, true
+}
+
+// OutgoingMetadata is the metadata the gateway sends for a request with
+// header from remoteAddr, for front ends that make their own calls.
+func OutgoingMetadata(header http.Header, remoteAddr string) metadata.MD {
+	md := metadata.MD{}
+	for name, values := range header {
+		if key, ok := incomingHeader(name); ok {
+			md.Append(key, values...)
+		} else if name == "Authorization" {
+			md.Append("authorization", values...)
+		}
+	}
+	host := remoteAddr
+	if h, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		host = h
+	}
+	md.Set(ForwardedForKey, host)
+	return md
+}
+
+// writeError writes the status of err as JSON under the HTTP status matching
+// its code, with ResourceExhausted for an oversized body. RetryInfo also
+// becomes Retry-After.
+func (g *Gateway) writeError(ctx context.Context, mux *runtime.ServeMux, m runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	if b, ok := r.Body.(*body); ok && b.over {
+		err = status.Errorf(codes.ResourceExhausted, "body is over %d bytes", b.max)
+	}
+	if delay, ok := rpcerror.Decode(err).RetryDelay(); ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+	}
+	runtime.DefaultHTTPErrorHandler(ctx, mux, m, w, r, err)
+}
+
+// writeRoutingError answers methods a path does not take with 405 rather
+// than the 501 their Unimplemented code maps to.
+func (g *Gateway) writeRoutingError(ctx context.Context, mux *runtime.ServeMux, m runtime.Marshaler, w http.ResponseWriter, r *http.Request, code int) {
+	if code != http.StatusMethodNotAllowed {
+		runtime.DefaultRoutingErrorHandler(ctx, mux, m, w, r, code)
+		return
+	}
+	err := status.Errorf(codes.Unimplemented, "%s is not allowed on %s", r.Method, r.URL.Path)
+	g.writeError(ctx, mux, m, w, r, &runtime.HTTPStatusError{HTTPStatus: code, Err: err})
+}
+
+// HTTPStatus maps a gRPC code to an HTTP status, as google.rpc.Code documents.
+func HTTPStatus(c codes.Code) int {
+	return runtime.HTTPStatusFromCode(c)
+}
+
+// eventStream writes responses as server-sent events: each message, stream
+// ones included, is the data of one event. Requests are read as JSON.
+type eventStream struct {
+	*runtime.JSONPb
+}
+
+func (eventStream) ContentType(interface{}) string { return eventStreamType }
+
+func (e eventStream) Marshal(v interface{}) ([]byte, error) {
+	b, err := e.JSONPb.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte("data: "), b...), nil
+}
+
+func (eventStream) Delimiter() []byte { return []byte("\n\n") }
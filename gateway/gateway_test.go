@@ -0,0 +1,67 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func TestOutgoingMetadata(t *testing.T) {
+	h := http.Header{}
+	for k, v := range map[string]string{
+		"Authorization":        "Bearer t",
+		"X-Api-Key":            "key",
+		"X-Forwarded-For":      "198.51.100.1",
+		"Traceparent":          "00-1-2-01",
+		"Grpc-Metadata-Tenant": "a",
+		"Accept":               "application/json",
+	} {
+		h.Set(k, v)
+	}
+	want := metadata.MD{
+		"authorization":   {"Bearer t"},
+		"x-api-key":       {"key"},
+		"traceparent":     {"00-1-2-01"},
+		"tenant":          {"a"},
+		"x-forwarded-for": {"192.0.2.1"},
+	}
+	if md := OutgoingMetadata(h, "192.0.2.1:1234"); !reflect.DeepEqual(md, want) {
+		t.Errorf("got %v", md)
+	}
+}
+
+func TestMaxBody(t *testing.T) {
+	if g, _ := New(nil, 0); g.maxBody != defaultMaxBody {
+		t.Errorf("default: %d", g.maxBody)
+	}
+	g, err := New(nil, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/messages", strings.NewReader(`{"message":"over sixteen bytes"}`)))
+	if w.Code != http.StatusTooManyRequests || !strings.Contains(w.Body.String(), "body is over 16 bytes") {
+		t.Errorf("over max body: %d %s", w.Code, w.Body)
+	}
+}
+
+func TestRoutingErrors(t *testing.T) {
+	g, err := New(nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/v1/ping", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("wrong method: %d %s", w.Code, w.Body)
+	}
+	w = httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/nothing", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("no route: %d %s", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,116 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Server implements grpc.health.v1.Health with a serving status per service,
+// and keeps the list of statuses for the admin endpoint. The empty service
+// name stands for the server as a whole. The grpc health.Server it wraps is
+// unexported so that every change goes through the Shutdown guard.
+type Server struct {
+	health *health.Server
+
+	mu       sync.Mutex
+	statuses map[string]healthpb.HealthCheckResponse_ServingStatus
+	shutdown bool
+}
+
+// New returns a Server reporting SERVING for the server and every given service.
+func New(services ...string) *Server {
+	s := &Server{
+		health:   health.NewServer(),
+		statuses: map[string]healthpb.HealthCheckResponse_ServingStatus{"": healthpb.HealthCheckResponse_SERVING},
+	}
+	for _, service := range services {
+		s.SetServingStatus(service, healthpb.HealthCheckResponse_SERVING)
+	}
+	return s
+}
+
+// SetServingStatus changes the status of service and notifies its watchers.
+// It has no effect after Shutdown.
+func (s *Server) SetServingStatus(service string, st healthpb.HealthCheckResponse_ServingStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.shutdown {
+		return
+	}
+	s.statuses[service] = st
+	s.health.SetServingStatus(service, st)
+}
+
+// Shutdown sets every service to NOT_SERVING and ignores later changes.
+func (s *Server) Shutdown() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.shutdown = true
+	for service := range s.statuses {
+		s.statuses[service] = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	s.health.Shutdown()
+}
+
+// Check reports the status of one service.
+func (s *Server) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	return s.health.Check(ctx, req)
+}
+
+// Watch sends the status of one service and then every change to it.
+func (s *Server) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	return s.health.Watch(req, stream)
+}
+
+// ServeHTTP lists the statuses on GET and changes one on POST,
+// e.g. POST /admin/health?service=simple.Simple&status=NOT_SERVING
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost, http.MethodPut:
+		v, ok := healthpb.HealthCheckResponse_ServingStatus_value[r.FormValue("status")]
+		if !ok {
+			http.Error(w, "status must be a serving status such as SERVING or NOT_SERVING", http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		shutdown := s.shutdown
+		s.mu.Unlock()
+		if shutdown {
+			http.Error(w, "server is shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		s.SetServingStatus(r.FormValue("service"), healthpb.HealthCheckResponse_ServingStatus(v))
+	default:
+		w.Header().Set("Allow", "GET, POST, PUT")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.mu.Lock()
+	statuses := map[string]string{}
+	for service, st := range s.statuses {
+		statuses[service] = st.String()
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statuses)
+}
+
+// ReadOnly serves ServeHTTP's GET only, for ports anyone can reach.
+func (s *Server) ReadOnly() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", "GET")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		s.ServeHTTP(w, r)
+	})
+}
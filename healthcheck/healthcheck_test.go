@@ -0,0 +1,172 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	health "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func client(t *testing.T, h *Server) health.HealthClient {
+	l := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	health.RegisterHealthServer(s, h)
+	go s.Serve(l)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "localhost", grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+		return l.Dial()
+	}), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return health.NewHealthClient(conn)
+}
+
+func TestCheck(t *testing.T) {
+	h := New("simple.Simple")
+	c := client(t, h)
+	ctx := context.Background()
+
+	for _, service := range []string{"", "simple.Simple"} {
+		resp, err := c.Check(ctx, &health.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Status != health.HealthCheckResponse_SERVING {
+			t.Errorf("%q is %s", service, resp.Status)
+		}
+	}
+
+	h.SetServingStatus("simple.Simple", health.HealthCheckResponse_NOT_SERVING)
+	resp, err := c.Check(ctx, &health.HealthCheckRequest{Service: "simple.Simple"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != health.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("simple.Simple is %s", resp.Status)
+	}
+
+	if _, err := c.Check(ctx, &health.HealthCheckRequest{Service: "unknown"}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	h := New("simple.Simple")
+	c := client(t, h)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := c.Watch(ctx, &health.HealthCheckRequest{Service: "simple.Simple"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := func(st health.HealthCheckResponse_ServingStatus) {
+		t.Helper()
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Status != st {
+			t.Errorf("expected %s, got %s", st, resp.Status)
+		}
+	}
+
+	expect(health.HealthCheckResponse_SERVING)
+	h.SetServingStatus("simple.Simple", health.HealthCheckResponse_NOT_SERVING)
+	expect(health.HealthCheckResponse_NOT_SERVING)
+	h.SetServingStatus("simple.Simple", health.HealthCheckResponse_SERVING)
+	expect(health.HealthCheckResponse_SERVING)
+	h.Shutdown()
+	expect(health.HealthCheckResponse_NOT_SERVING)
+
+	h.SetServingStatus("simple.Simple", health.HealthCheckResponse_SERVING)
+	resp, err := c.Check(context.Background(), &health.HealthCheckRequest{Service: "simple.Simple"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != health.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("status changed after shutdown: %s", resp.Status)
+	}
+	if _, ok := interface{}(h).(interface{ Resume() }); ok {
+		t.Error("Resume would bring services back after shutdown")
+	}
+}
+
+func TestWatchUnknown(t *testing.T) {
+	h := New()
+	c := client(t, h)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := c.Watch(ctx, &health.HealthCheckRequest{Service: "simple.Simple"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != health.HealthCheckResponse_SERVICE_UNKNOWN {
+		t.Errorf("expected SERVICE_UNKNOWN, got %s", resp.Status)
+	}
+
+	h.SetServingStatus("simple.Simple", health.HealthCheckResponse_SERVING)
+	resp, err = stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != health.HealthCheckResponse_SERVING {
+		t.Errorf("expected SERVING, got %s", resp.Status)
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	h := New("simple.Simple")
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/health?service=simple.Simple&status=NOT_SERVING", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", rec.Code, rec.Body)
+	}
+	var statuses map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&statuses); err != nil {
+		t.Fatal(err)
+	}
+	if statuses["simple.Simple"] != "NOT_SERVING" || statuses[""] != "SERVING" {
+		t.Errorf("unexpected statuses %v", statuses)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/health?status=BROKEN", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("unexpected status %d", rec.Code)
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	h := New("simple.Simple")
+
+	rec := httptest.NewRecorder()
+	h.ReadOnly().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/health?service=simple.Simple&status=NOT_SERVING", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("unexpected status %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	h.ReadOnly().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/health", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"simple.Simple":"SERVING"`) {
+		t.Errorf("unexpected response %d %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,135 @@
+package ids
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	nodeBits = 7
+	seqBits  = 31 - nodeBits
+	maxNode  = 1<<nodeBits - 1
+
+	// reserveBlock is how many ids are handed out between writes of the state file.
+	reserveBlock = 1000
+)
+
+var ErrExhausted = errors.New("id space is exhausted")
+
+// Allocator hands out the Id of the pb.Name assigned by PutMessage.
+type Allocator interface {
+	Next() (int32, error)
+}
+
+// New returns the Allocator selected by mode.
+// "counter" (default) is monotonic and never repeats an id; with a node greater than 0
+// the node is put in the upper bits so several servers can share one id space,
+// and with a statePath the counter survives restarts.
+// "random" keeps the original behavior of picking a number below 100.
+func New(mode string, node int, statePath string) (Allocator, error) {
+	switch mode {
+	case "", "counter":
+		return NewCounter(node, statePath)
+	case "random":
+		return NewRandom(100), nil
+	}
+	return nil, fmt.Errorf("unknown id mode: %s", mode)
+}
+
+type counter struct {
+	seq      int64
+	reserved int64
+	max      int64
+	prefix   int64
+
+	mu   sync.Mutex
+	path string
+}
+
+func NewCounter(node int, statePath string) (Allocator, error) {
+	if node < 0 || node > maxNode {
+		return nil, fmt.Errorf("node must be between 0 and %d: %d", maxNode, node)
+	}
+	c := &counter{max: math.MaxInt32, path: statePath}
+	if node > 0 {
+		c.prefix = int64(node) << seqBits
+		c.max = 1<<seqBits - 1
+	}
+	if statePath != "" {
+		data, err := os.ReadFile(statePath)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if len(data) > 0 {
+			saved, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("broken id state %s: %w", statePath, err)
+			}
+			c.seq = saved
+			c.reserved = saved
+		}
+	}
+	return c, nil
+}
+
+func (c *counter) Next() (int32, error) {
+	seq := atomic.AddInt64(&c.seq, 1)
+	if seq > c.max {
+		return 0, ErrExhausted
+	}
+	if c.path != "" {
+		if err := c.reserve(seq); err != nil {
+			return 0, err
+		}
+	}
+	return int32(c.prefix | seq), nil
+}
+
+// reserve makes sure the state file records a value at least as large as seq,
+// so that a restarted server never hands out an id twice.
+func (c *counter) reserve(seq int64) error {
+	if seq <= atomic.LoadInt64(&c.reserved) {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if seq <= c.reserved {
+		return nil
+	}
+
+	next := seq + reserveBlock
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.FormatInt(next, 10)), 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return err
+	}
+	atomic.StoreInt64(&c.reserved, next)
+	return nil
+}
+
+type random struct {
+	mu  sync.Mutex
+	rnd *rand.Rand
+	n   int
+}
+
+// NewRandom picks ids below n; ids are not unique.
+func NewRandom(n int) Allocator {
+	return &random{rnd: rand.New(rand.NewSource(time.Now().UnixNano())), n: n}
+}
+
+func (r *random) Next() (int32, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return int32(r.rnd.Intn(r.n)), nil
+}
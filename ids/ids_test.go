@@ -0,0 +1,104 @@
+package ids
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestCounterUnique(t *testing.T) {
+	a, err := NewCounter(0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	seen := map[int32]bool{}
+	var wg sync.WaitGroup
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				id, err := a.Next()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				mu.Lock()
+				if seen[id] {
+					t.Errorf("id %d is allocated twice", id)
+				}
+				seen[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(seen) != 10000 {
+		t.Errorf("expected 10000 ids, got %d", len(seen))
+	}
+}
+
+func TestCounterNode(t *testing.T) {
+	a, err := NewCounter(3, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := a.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id>>seqBits != 3 || id&(1<<seqBits-1) != 1 {
+		t.Errorf("unexpected id %d", id)
+	}
+
+	if _, err := NewCounter(maxNode+1, ""); err == nil {
+		t.Error("expected error for too large node")
+	}
+}
+
+func TestCounterPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ids")
+
+	a, err := NewCounter(0, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var last int32
+	for i := 0; i < reserveBlock+10; i++ {
+		if last, err = a.Next(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b, err := NewCounter(0, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := b.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id <= last {
+		t.Errorf("id %d after restart is not greater than %d", id, last)
+	}
+}
+
+func TestNew(t *testing.T) {
+	a, err := New("random", 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		id, _ := a.Next()
+		if id < 0 || id >= 100 {
+			t.Errorf("unexpected random id %d", id)
+		}
+	}
+
+	if _, err := New("unknown", 0, ""); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+}
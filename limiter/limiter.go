@@ -0,0 +1,359 @@
+// Package limiter rejects calls beyond per-client rate limits, concurrent
+// streams and messages per stream with codes.ResourceExhausted.
+package limiter
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/shin5ok/proto-grpc-simple/auth"
+	"github.com/shin5ok/proto-grpc-simple/rpcerror"
+)
+
+// Reasons for a rejection, as in the reason attribute of the rejection metric.
+const (
+	ReasonRate     = "rate"
+	ReasonStreams  = "streams"
+	ReasonMessages = "messages"
+)
+
+// errorReasons are the ErrorInfo reasons of the rejections.
+var errorReasons = map[string]string{
+	ReasonRate:     rpcerror.ReasonRateLimited,
+	ReasonStreams:  rpcerror.ReasonTooManyStreams,
+	ReasonMessages: rpcerror.ReasonTooManyMessages,
+}
+
+// streamRetryDelay is what RetryInfo suggests when a client has too many streams open.
+const streamRetryDelay = time.Second
+
+// sweepInterval is how often buckets that filled up again are dropped.
+const sweepInterval = time.Minute
+
+// Rate is a token bucket: Burst calls at once, refilled at Limit per second.
+// A zero Limit means no limit.
+type Rate struct {
+	Limit float64
+	Burst int
+}
+
+// ParseRate reads "limit" or "limit/burst". Burst defaults to the limit rounded up, at least 1.
+func ParseRate(s string) (Rate, error) {
+	limit, burst, hasBurst := strings.Cut(strings.TrimSpace(s), "/")
+	var r Rate
+	var err error
+	if r.Limit, err = strconv.ParseFloat(limit, 64); err != nil || r.Limit < 0 {
+		return Rate{}, fmt.Errorf("invalid rate %q: want limit or limit/burst", s)
+	}
+	if hasBurst {
+		if r.Burst, err = strconv.Atoi(burst); err != nil || r.Burst < 1 {
+			return Rate{}, fmt.Errorf("invalid burst in %q", s)
+		}
+	}
+	return r.withDefaultBurst(), nil
+}
+
+func (r Rate) withDefaultBurst() Rate {
+	if r.Burst == 0 && r.Limit > 0 {
+		r.Burst = int(math.Max(1, math.Ceil(r.Limit)))
+	}
+	return r
+}
+
+// ParseMethods reads "Method=limit[/burst]" entries into Options.Methods.
+// Methods without a leading slash belong to service, as in auth.ParsePolicy.
+func ParseMethods(service string, entries []string) (map[string]Rate, error) {
+	rates := map[string]Rate{}
+	for _, e := range entries {
+		method, rate, ok := strings.Cut(e, "=")
+		method = strings.TrimSpace(method)
+		if !ok || method == "" {
+			return nil, fmt.Errorf("invalid limit %q: want Method=limit[/burst]", e)
+		}
+		if !strings.HasPrefix(method, "/") {
+			method = "/" + service + "/" + method
+		}
+		r, err := ParseRate(rate)
+		if err != nil {
+			return nil, fmt.Errorf("limit for %s: %w", method, err)
+		}
+		rates[method] = r
+	}
+	return rates, nil
+}
+
+// Options configure a Limiter. Zero values turn the matching limit off.
+type Options struct {
+	// Rate applies to each client on each method without an entry in Methods.
+	Rate Rate
+	// Methods are rates by full method name ("/simple.Simple/PutMessage") or
+	// by service ending in a slash ("/grpc.health.v1.Health/").
+	Methods map[string]Rate
+	// Key tells clients apart: peer (address, the default), principal (the
+	// authenticated caller, falling back to the address) or header:<name>
+	// (a metadata value, falling back to the address).
+	Key string
+	// MaxStreams is how many streaming calls a client may have open at once.
+	MaxStreams int
+	// MaxStreamMessages is how many messages a stream may receive, and send.
+	MaxStreamMessages int
+	// Exempt lists methods, or services ending in a slash, that are not limited at all.
+	Exempt        []string
+	MeterProvider metric.MeterProvider
+}
+
+// Limiter keeps a token bucket per client and method, and counts the open streams of each client.
+type Limiter struct {
+	o        Options
+	header   string
+	rejected metric.Int64Counter
+	now      func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[bucketKey]*bucket
+	streams   map[string]int
+	lastSweep time.Time
+}
+
+type bucketKey struct {
+	client, method string
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	rate   Rate
+}
+
+// New returns a Limiter for o, which is ready for use by concurrent calls.
+func New(o Options) (*Limiter, error) {
+	l := &Limiter{
+		o:       o,
+		now:     time.Now,
+		buckets: map[bucketKey]*bucket{},
+		streams: map[string]int{},
+	}
+	switch {
+	case o.Key == "" || o.Key == "peer" || o.Key == "principal":
+	case strings.HasPrefix(o.Key, "header:") && len(o.Key) > len("header:"):
+		l.header = strings.ToLower(strings.TrimPrefix(o.Key, "header:"))
+	default:
+		return nil, fmt.Errorf("unknown limiter key %q: want peer, principal or header:<name>", o.Key)
+	}
+	l.o.Rate = o.Rate.withDefaultBurst()
+	if o.MeterProvider != nil {
+		l.rejected, _ = o.MeterProvider.Meter("github.com/shin5ok/proto-grpc-simple/limiter").Int64Counter("simple.limiter.rejected",
+			metric.WithDescription("Calls and messages rejected by the limiter."))
+	}
+	return l, nil
+}
+
+func (l *Limiter) exempt(method string) bool {
+	for _, e := range l.o.Exempt {
+		if method == e || strings.HasSuffix(e, "/") && strings.HasPrefix(method, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// rate returns the rate of method: its own, its service's or the default.
+func (l *Limiter) rate(method string) Rate {
+	if r, ok := l.o.Methods[method]; ok {
+		return r
+	}
+	if i := strings.LastIndex(method, "/"); i > 0 {
+		if r, ok := l.o.Methods[method[:i+1]]; ok {
+			return r
+		}
+	}
+	return l.o.Rate
+}
+
+// client names the caller of ctx by the configured key.
+func (l *Limiter) client(ctx context.Context) string {
+	switch {
+	case l.o.Key == "principal":
+		if p, ok := auth.FromContext(ctx); ok {
+			return p.Kind + ":" + p.Subject
+		}
+	case l.header != "":
+		md, _ := metadata.FromIncomingContext(ctx)
+		if v := md.Get(l.header); len(v) > 0 && v[0] != "" {
+			return "header:" + v[0]
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		addr := p.Addr.String()
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			return host
+		}
+		return addr
+	}
+	return ""
+}
+
+// allow takes a token from the bucket of client and method, or tells how long until there is one.
+func (l *Limiter) allow(client, method string) (bool, time.Duration) {
+	r := l.rate(method)
+	if r.Limit <= 0 {
+		return true, 0
+	}
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+	key := bucketKey{client, method}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(r.Burst), last: now, rate: r}
+		l.buckets[key] = b
+	}
+	b.refill(now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / r.Limit * float64(time.Second))
+}
+
+func (b *bucket) refill(now time.Time) {
+	b.tokens = math.Min(float64(b.rate.Burst), b.tokens+now.Sub(b.last).Seconds()*b.rate.Limit)
+	b.last = now
+}
+
+// sweep drops full buckets, which behave like new ones, so clients that went
+// away do not keep memory. l.mu must be held.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for k, b := range l.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.rate.Burst) {
+			delete(l.buckets, k)
+		}
+	}
+}
+
+// openStream counts a stream of client, or reports that it has too many open.
+func (l *Limiter) openStream(client string) bool {
+	if l.o.MaxStreams <= 0 {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.streams[client] >= l.o.MaxStreams {
+		return false
+	}
+	l.streams[client]++
+	return true
+}
+
+func (l *Limiter) closeStream(client string) {
+	if l.o.MaxStreams <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.streams[client]--; l.streams[client] <= 0 {
+		delete(l.streams, client)
+	}
+}
+
+func (l *Limiter) reject(ctx context.Context, method, reason string, retry time.Duration, description string) error {
+	if l.rejected != nil {
+		l.rejected.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("rpc.method", method),
+			attribute.String("reason", reason),
+		))
+	}
+	violations := []*errdetails.QuotaFailure_Violation{{Subject: reason + ":" + method, Description: description}}
+	return rpcerror.New(codes.ResourceExhausted, errorReasons[reason], description,
+		rpcerror.WithMetadata("method", method),
+		rpcerror.WithRetry(retry),
+		rpcerror.WithDetails(&errdetails.QuotaFailure{Violations: violations}))
+}
+
+func (l *Limiter) UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if l.exempt(info.FullMethod) {
+		return handler(ctx, req)
+	}
+	if ok, retry := l.allow(l.client(ctx), info.FullMethod); !ok {
+		return nil, l.reject(ctx, info.FullMethod, ReasonRate, retry, "rate limit exceeded")
+	}
+	return handler(ctx, req)
+}
+
+func (l *Limiter) StreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if l.exempt(info.FullMethod) {
+		return handler(srv, ss)
+	}
+	ctx := ss.Context()
+	client := l.client(ctx)
+	if ok, retry := l.allow(client, info.FullMethod); !ok {
+		return l.reject(ctx, info.FullMethod, ReasonRate, retry, "rate limit exceeded")
+	}
+	if !l.openStream(client) {
+		return l.reject(ctx, info.FullMethod, ReasonStreams, streamRetryDelay,
+			fmt.Sprintf("more than %d concurrent streams", l.o.MaxStreams))
+	}
+	defer l.closeStream(client)
+
+	if l.o.MaxStreamMessages > 0 {
+		ss = &countingStream{ServerStream: ss, l: l, method: info.FullMethod}
+	}
+	return handler(srv, ss)
+}
+
+// countingStream fails RecvMsg and SendMsg once MaxStreamMessages went through either way.
+type countingStream struct {
+	grpc.ServerStream
+	l              *Limiter
+	method         string
+	received, sent int
+}
+
+func (s *countingStream) RecvMsg(m interface{}) error {
+	if s.received >= s.l.o.MaxStreamMessages {
+		// Let the end of the stream through, so a client sending exactly the maximum succeeds.
+		if err := s.ServerStream.RecvMsg(m); err != nil {
+			return err
+		}
+		return s.l.reject(s.Context(), s.method, ReasonMessages, 0,
+			fmt.Sprintf("more than %d messages in one stream", s.l.o.MaxStreamMessages))
+	}
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.received++
+	}
+	return err
+}
+
+func (s *countingStream) SendMsg(m interface{}) error {
+	if s.sent >= s.l.o.MaxStreamMessages {
+		return s.l.reject(s.Context(), s.method, ReasonMessages, 0,
+			fmt.Sprintf("more than %d messages in one stream", s.l.o.MaxStreamMessages))
+	}
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent++
+	}
+	return err
+}
@@ -0,0 +1,223 @@
+package limiter
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/shin5ok/proto-grpc-simple/auth"
+)
+
+func fromAddr(addr string) context.Context {
+	a, _ := net.ResolveTCPAddr("tcp", addr)
+	return peer.NewContext(context.Background(), &peer.Peer{Addr: a})
+}
+
+func retryDelay(t *testing.T, err error) time.Duration {
+	t.Helper()
+	st := status.Convert(err)
+	if st.Code() != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted, got %v", err)
+	}
+	for _, d := range st.Details() {
+		if r, ok := d.(*errdetails.RetryInfo); ok {
+			return r.RetryDelay.AsDuration()
+		}
+	}
+	return 0
+}
+
+func TestParseRate(t *testing.T) {
+	for s, want := range map[string]Rate{
+		"10":    {10, 10},
+		"0.5":   {0.5, 1},
+		"2/5":   {2, 5},
+		"0":     {0, 0},
+		" 3/1 ": {3, 1},
+	} {
+		if got, err := ParseRate(s); err != nil || got != want {
+			t.Errorf("%q: got %v, %v", s, got, err)
+		}
+	}
+	for _, s := range []string{"", "fast", "-1", "1/0", "1/x"} {
+		if _, err := ParseRate(s); err == nil {
+			t.Errorf("%q: expected an error", s)
+		}
+	}
+
+	m, err := ParseMethods("simple.Simple", []string{"PutMessage=1/2", "/grpc.health.v1.Health/=0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m["/simple.Simple/PutMessage"] != (Rate{1, 2}) || m["/grpc.health.v1.Health/"] != (Rate{}) {
+		t.Errorf("got %v", m)
+	}
+	if _, err := ParseMethods("simple.Simple", []string{"PutMessage"}); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestRate(t *testing.T) {
+	l, _ := New(Options{
+		Rate:    Rate{Limit: 1, Burst: 2},
+		Methods: map[string]Rate{"/simple.Simple/PingPong": {}, "/other.Service/": {Limit: 10, Burst: 1}},
+	})
+	now := time.Unix(0, 0)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.allow("a", "/simple.Simple/GetMessage"); !ok {
+			t.Fatalf("call %d within the burst was rejected", i)
+		}
+	}
+	ok, retry := l.allow("a", "/simple.Simple/GetMessage")
+	if ok || retry != time.Second {
+		t.Errorf("got %v, retry after %s", ok, retry)
+	}
+	if ok, _ := l.allow("b", "/simple.Simple/GetMessage"); !ok {
+		t.Error("another client was rejected")
+	}
+	if ok, _ := l.allow("a", "/simple.Simple/PutMessage"); !ok {
+		t.Error("another method was rejected")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if ok, retry := l.allow("a", "/simple.Simple/GetMessage"); ok || retry != 500*time.Millisecond {
+		t.Errorf("half refilled: got %v, retry after %s", ok, retry)
+	}
+	now = now.Add(500 * time.Millisecond)
+	if ok, _ := l.allow("a", "/simple.Simple/GetMessage"); !ok {
+		t.Error("not refilled")
+	}
+
+	for i := 0; i < 5; i++ {
+		if ok, _ := l.allow("a", "/simple.Simple/PingPong"); !ok {
+			t.Fatal("unlimited method was rejected")
+		}
+	}
+	l.allow("a", "/other.Service/Call")
+	if ok, retry := l.allow("a", "/other.Service/Call"); ok || retry != 100*time.Millisecond {
+		t.Errorf("service rate: got %v, retry after %s", ok, retry)
+	}
+
+	now = now.Add(time.Hour)
+	l.allow("c", "/simple.Simple/GetMessage")
+	if len(l.buckets) != 1 {
+		t.Errorf("%d buckets left after a sweep", len(l.buckets))
+	}
+}
+
+func TestClient(t *testing.T) {
+	ctx := fromAddr("10.0.0.1:1234")
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-client-id", "load-test"))
+
+	for key, want := range map[string]string{
+		"":                   "10.0.0.1",
+		"peer":               "10.0.0.1",
+		"principal":          "10.0.0.1",
+		"header:X-Client-Id": "header:load-test",
+		"header:x-other":     "10.0.0.1",
+	} {
+		l, err := New(Options{Key: key})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := l.client(ctx); got != want {
+			t.Errorf("%q: got %q, want %q", key, got, want)
+		}
+	}
+
+	l, _ := New(Options{Key: "principal"})
+	if got := l.client(auth.NewContext(ctx, &auth.Principal{Subject: "ci", Kind: auth.KindAPIKey})); got != "api_key:ci" {
+		t.Errorf("got %q", got)
+	}
+	if _, err := New(Options{Key: "cookie"}); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+type fakeStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	incoming int
+}
+
+func (s *fakeStream) Context() context.Context    { return s.ctx }
+func (s *fakeStream) SendMsg(m interface{}) error { return nil }
+func (s *fakeStream) RecvMsg(m interface{}) error {
+	if s.incoming == 0 {
+		return context.Canceled
+	}
+	s.incoming--
+	return nil
+}
+
+func TestStreams(t *testing.T) {
+	l, _ := New(Options{MaxStreams: 1, MaxStreamMessages: 2, Exempt: []string{"/grpc.health.v1.Health/"}})
+	info := &grpc.StreamServerInfo{FullMethod: "/simple.Simple/ListMessage"}
+	ctx := fromAddr("10.0.0.1:1")
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go l.StreamServerInterceptor(nil, &fakeStream{ctx: ctx}, info, func(interface{}, grpc.ServerStream) error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+
+	noop := func(interface{}, grpc.ServerStream) error { return nil }
+	if retry := retryDelay(t, l.StreamServerInterceptor(nil, &fakeStream{ctx: ctx}, info, noop)); retry != streamRetryDelay {
+		t.Errorf("retry after %s", retry)
+	}
+	if err := l.StreamServerInterceptor(nil, &fakeStream{ctx: fromAddr("10.0.0.2:1")}, info, noop); err != nil {
+		t.Errorf("another client: %v", err)
+	}
+	health := &grpc.StreamServerInfo{FullMethod: "/grpc.health.v1.Health/Watch"}
+	if err := l.StreamServerInterceptor(nil, &fakeStream{ctx: ctx}, health, noop); err != nil {
+		t.Errorf("exempt method: %v", err)
+	}
+	close(release)
+
+	send := func(_ interface{}, ss grpc.ServerStream) error {
+		for i := 0; i < 3; i++ {
+			if err := ss.SendMsg(nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	// The first stream may still be finishing.
+	var err error
+	for i := 0; i < 100; i++ {
+		if err = l.StreamServerInterceptor(nil, &fakeStream{ctx: ctx}, info, send); status.Code(err) != codes.ResourceExhausted || retryDelay(t, err) == 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if retryDelay(t, err) != 0 {
+		t.Errorf("too many messages suggested a retry: %v", err)
+	}
+
+	recv := func(_ interface{}, ss grpc.ServerStream) error {
+		for {
+			if err := ss.RecvMsg(nil); err != nil {
+				return err
+			}
+		}
+	}
+	if err := l.StreamServerInterceptor(nil, &fakeStream{ctx: ctx, incoming: 2}, info, recv); err != context.Canceled {
+		t.Errorf("exactly the maximum: %v", err)
+	}
+	if err := l.StreamServerInterceptor(nil, &fakeStream{ctx: ctx, incoming: 3}, info, recv); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("one more than the maximum: %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+// Config selects how the server logs.
+//
+//	Level:     trace, debug, info, warn or error (default debug)
+//	Format:    json (default) or console
+//	Trace:     cloud writes logging.googleapis.com/trace and spanId (default),
+//	           otel writes trace_id, span_id and trace_flags
+//	ProjectID: with the cloud format, trace ids become projects/<id>/traces/<trace>
+//	Payload:   on (default), off or redact
+type Config struct {
+	Level     string
+	Format    string
+	Trace     string
+	ProjectID string
+	Payload   string
+}
+
+// New builds a logger writing to w. Events logged through a logger bound to
+// a context, as Ctx returns, carry the trace and span of that context.
+func New(c Config, w io.Writer) (zerolog.Logger, error) {
+	level := zerolog.DebugLevel
+	if c.Level != "" {
+		l, err := zerolog.ParseLevel(strings.ToLower(c.Level))
+		if err != nil {
+			return zerolog.Nop(), fmt.Errorf("unknown log level %q", c.Level)
+		}
+		level = l
+	}
+
+	switch c.Format {
+	case "", "json":
+	case "console":
+		w = zerolog.ConsoleWriter{Out: w}
+	default:
+		return zerolog.Nop(), fmt.Errorf("unknown log format %q", c.Format)
+	}
+
+	hook := traceHook{projectID: c.ProjectID}
+	switch c.Trace {
+	case "", "cloud":
+	case "otel":
+		hook.otel = true
+	default:
+		return zerolog.Nop(), fmt.Errorf("unknown log trace format %q", c.Trace)
+	}
+
+	if _, err := ParsePayloadMode(c.Payload); err != nil {
+		return zerolog.Nop(), err
+	}
+
+	return zerolog.New(w).Level(level).With().Timestamp().Logger().Hook(hook), nil
+}
+
+type traceHook struct {
+	otel      bool
+	projectID string
+}
+
+func (h traceHook) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
+	sc := trace.SpanContextFromContext(e.GetCtx())
+	if !sc.IsValid() {
+		return
+	}
+	if h.otel {
+		e.Str("trace_id", sc.TraceID().String()).
+			Str("span_id", sc.SpanID().String()).
+			Str("trace_flags", sc.TraceFlags().String())
+		return
+	}
+	traceID := sc.TraceID().String()
+	if h.projectID != "" {
+		traceID = "projects/" + h.projectID + "/traces/" + traceID
+	}
+	e.Str("logging.googleapis.com/trace", traceID).
+		Str("logging.googleapis.com/spanId", sc.SpanID().String()).
+		Bool("logging.googleapis.com/trace_sampled", sc.IsSampled())
+}
+
+type ctxKey struct{}
+
+// NewContext returns a copy of ctx carrying l.
+func NewContext(ctx context.Context, l zerolog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// Ctx returns the logger carried by ctx, or the global logger, bound to ctx
+// so that its events carry the current trace and span.
+func Ctx(ctx context.Context) *zerolog.Logger {
+	l, ok := ctx.Value(ctxKey{}).(zerolog.Logger)
+	if !ok {
+		l = log.Logger
+	}
+	l = l.With().Ctx(ctx).Logger()
+	return &l
+}
+
+func withMethod(l zerolog.Logger, fullMethod string) zerolog.Logger {
+	return l.With().
+		Str("grpc.service", path.Dir(fullMethod)[1:]).
+		Str("grpc.method", path.Base(fullMethod)).
+		Logger()
+}
+
+// UnaryServerInterceptor puts a logger naming the called method into the context of each call.
+func UnaryServerInterceptor(l zerolog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(NewContext(ctx, withMethod(l, info.FullMethod)), req)
+	}
+}
+
+// StreamServerInterceptor puts a logger naming the called method into the context of each stream.
+func StreamServerInterceptor(l zerolog.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := NewContext(ss.Context(), withMethod(l, info.FullMethod))
+		return handler(srv, StreamWithContext(ctx, ss))
+	}
+}
+
+// StreamWithContext returns ss with its context replaced by ctx, for stream
+// interceptors that add to the context.
+func StreamWithContext(ctx context.Context, ss grpc.ServerStream) grpc.ServerStream {
+	return &contextStream{ServerStream: ss, ctx: ctx}
+}
+
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,196 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	pb "github.com/shin5ok/proto-grpc-simple/pb"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+func spanContext() context.Context {
+	traceID, _ := trace.TraceIDFromHex("0102030405060708090a0b0c0d0e0f10")
+	spanID, _ := trace.SpanIDFromHex("0102030405060708")
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+	return trace.ContextWithSpanContext(context.Background(), sc)
+}
+
+func decode(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var e map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("%s: %v", line, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestNew(t *testing.T) {
+	for _, c := range []Config{
+		{Level: "loud"},
+		{Format: "xml"},
+		{Trace: "zipkin"},
+		{Payload: "maybe"},
+	} {
+		if _, err := New(c, &bytes.Buffer{}); err == nil {
+			t.Errorf("%+v: expected an error", c)
+		}
+	}
+
+	var buf bytes.Buffer
+	l, err := New(Config{Level: "warn"}, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Info().Msg("hidden")
+	l.Warn().Msg("shown")
+	if entries := decode(t, &buf); len(entries) != 1 || entries[0]["message"] != "shown" {
+		t.Errorf("got %v", entries)
+	}
+}
+
+func TestCtx(t *testing.T) {
+	ctx := spanContext()
+
+	tests := []struct {
+		config Config
+		want   map[string]interface{}
+	}{
+		{Config{}, map[string]interface{}{
+			"logging.googleapis.com/trace":         "0102030405060708090a0b0c0d0e0f10",
+			"logging.googleapis.com/spanId":        "0102030405060708",
+			"logging.googleapis.com/trace_sampled": true,
+		}},
+		{Config{ProjectID: "p"}, map[string]interface{}{
+			"logging.googleapis.com/trace": "projects/p/traces/0102030405060708090a0b0c0d0e0f10",
+		}},
+		{Config{Trace: "otel"}, map[string]interface{}{
+			"trace_id":    "0102030405060708090a0b0c0d0e0f10",
+			"span_id":     "0102030405060708",
+			"trace_flags": "01",
+		}},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		l, err := New(tt.config, &buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		Ctx(NewContext(ctx, l)).Info().Msg("hello")
+		l.Info().Msg("no trace")
+
+		entries := decode(t, &buf)
+		if len(entries) != 2 {
+			t.Fatalf("%+v: got %v", tt.config, entries)
+		}
+		for k, v := range tt.want {
+			if entries[0][k] != v {
+				t.Errorf("%+v: %s is %v, want %v", tt.config, k, entries[0][k], v)
+			}
+			if _, ok := entries[1][k]; ok {
+				t.Errorf("%+v: %s is set without a span", tt.config, k)
+			}
+		}
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	var buf bytes.Buffer
+	l, _ := New(Config{}, &buf)
+	info := &grpc.UnaryServerInfo{FullMethod: "/simple.Simple/PingPong"}
+	UnaryServerInterceptor(l)(spanContext(), nil, info, func(ctx context.Context, _ interface{}) (interface{}, error) {
+		Ctx(ctx).Info().Msg("handled")
+		return nil, nil
+	})
+
+	entries := decode(t, &buf)
+	if len(entries) != 1 {
+		t.Fatalf("got %v", entries)
+	}
+	if entries[0]["grpc.service"] != "simple.Simple" || entries[0]["grpc.method"] != "PingPong" {
+		t.Errorf("got %v", entries[0])
+	}
+	if entries[0]["logging.googleapis.com/spanId"] != "0102030405060708" {
+		t.Errorf("got %v", entries[0])
+	}
+}
+
+func TestRedact(t *testing.T) {
+	m := &pb.BulkPutSummary{
+		Accepted: 1,
+		Names:    []*pb.Name{{Text: "secret", Id: 7}},
+		Errors:   []*pb.BulkPutError{{Index: 2, Message: "secret"}},
+	}
+	r := Redact(m).(*pb.BulkPutSummary)
+	if r.Accepted != 1 || r.Names[0].Id != 7 || r.Errors[0].Index != 2 {
+		t.Errorf("non-string fields changed: %v", r)
+	}
+	if r.Names[0].Text != Redacted || r.Errors[0].Message != Redacted {
+		t.Errorf("strings not redacted: %v", r)
+	}
+	if m.Names[0].Text != "secret" {
+		t.Errorf("original changed: %v", m)
+	}
+
+	p := Redact(&pb.Message{Message: "secret", Payload: []byte("secret")}).(*pb.Message)
+	if p.Message != Redacted || p.Payload != nil {
+		t.Errorf("got %v", p)
+	}
+}
+
+func TestPayloadUnaryServerInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/simple.Simple/PingPong"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &pb.Message{Message: "Pong"}, nil
+	}
+
+	tests := []struct {
+		mode PayloadMode
+		want []string
+	}{
+		{PayloadOn, []string{`"message":"Ping"`, `"message":"Pong"`}},
+		{PayloadRedact, []string{`"message":"[REDACTED]"`, `"message":"[REDACTED]"`}},
+		{PayloadOff, nil},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		l, _ := New(Config{Trace: "otel"}, &buf)
+		ctx := NewContext(spanContext(), l)
+		res, err := PayloadUnaryServerInterceptor(tt.mode)(ctx, &pb.Message{Message: "Ping"}, info, handler)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.(*pb.Message).Message != "Pong" {
+			t.Errorf("%s: response changed to %v", tt.mode, res)
+		}
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(tt.want) == 0 {
+			if buf.Len() != 0 {
+				t.Errorf("%s: logged %s", tt.mode, buf.String())
+			}
+			continue
+		}
+		if len(lines) != len(tt.want) {
+			t.Fatalf("%s: got %s", tt.mode, buf.String())
+		}
+		for i, want := range tt.want {
+			if !strings.Contains(lines[i], want) || !strings.Contains(lines[i], `"trace_id":"0102030405060708090a0b0c0d0e0f10"`) {
+				t.Errorf("%s: %s does not contain %s and the trace id", tt.mode, lines[i], want)
+			}
+		}
+	}
+}
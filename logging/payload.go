@@ -0,0 +1,197 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+type PayloadMode string
+
+const (
+	PayloadOn     PayloadMode = "on"
+	PayloadOff    PayloadMode = "off"
+	PayloadRedact PayloadMode = "redact"
+)
+
+// Redacted replaces the value of every string field in redacted payloads.
+// Bytes fields are cleared.
+const Redacted = "[REDACTED]"
+
+// Payloads are logged at debug level under the keys grpc_zerolog uses.
+const (
+	payloadLevel    = zerolog.DebugLevel
+	requestPayload  = "grpc.request.payload"
+	responsePayload = "grpc.response.payload"
+)
+
+func ParsePayloadMode(s string) (PayloadMode, error) {
+	switch m := PayloadMode(s); m {
+	case "":
+		return PayloadOn, nil
+	case PayloadOn, PayloadOff, PayloadRedact:
+		return m, nil
+	}
+	return "", fmt.Errorf("unknown payload logging mode %q", s)
+}
+
+// Payload adds m to e under key as mode allows: as it is when payloads are on,
+// with Redact applied when they are redacted, and not at all when they are off.
+// Handlers use it for payloads they log themselves.
+func (mode PayloadMode) Payload(e *zerolog.Event, key string, m proto.Message) *zerolog.Event {
+	if p := mode.Payloads(m); p != nil {
+		e = e.Str(key, fmt.Sprintf("%+v", p[0]))
+	}
+	return e
+}
+
+// Payloads returns ms as mode lets them into logs, or nil when payloads are off.
+func (mode PayloadMode) Payloads(ms ...proto.Message) []proto.Message {
+	switch mode {
+	case PayloadOff:
+		return nil
+	case PayloadRedact:
+		redacted := make([]proto.Message, len(ms))
+		for i, m := range ms {
+			redacted[i] = Redact(m)
+		}
+		return redacted
+	}
+	return ms
+}
+
+// PayloadUnaryServerInterceptor logs requests and responses, with their string
+// and bytes fields redacted, or not at all, depending on mode. It logs with
+// the logger of the call's context, so it goes after the interceptors that
+// start the span and put the logger there.
+func PayloadUnaryServerInterceptor(mode PayloadMode) grpc.UnaryServerInterceptor {
+	if mode == PayloadOff {
+		return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			return handler(ctx, req)
+		}
+	}
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		l := *Ctx(ctx)
+		mode.logPayload(l, requestPayload, req)
+		res, err := handler(ctx, req)
+		if err == nil {
+			mode.logPayload(l, responsePayload, res)
+		}
+		return res, err
+	}
+}
+
+// PayloadStreamServerInterceptor is the streaming counterpart of PayloadUnaryServerInterceptor,
+// logging every message sent or received.
+func PayloadStreamServerInterceptor(mode PayloadMode) grpc.StreamServerInterceptor {
+	if mode == PayloadOff {
+		return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			return handler(srv, ss)
+		}
+	}
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &payloadStream{ServerStream: ss, l: *Ctx(ss.Context()), mode: mode})
+	}
+}
+
+type payloadStream struct {
+	grpc.ServerStream
+	l    zerolog.Logger
+	mode PayloadMode
+}
+
+func (s *payloadStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.mode.logPayload(s.l, responsePayload, m)
+	}
+	return err
+}
+
+func (s *payloadStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.mode.logPayload(s.l, requestPayload, m)
+	}
+	return err
+}
+
+func (mode PayloadMode) logPayload(l zerolog.Logger, key string, m interface{}) {
+	p, ok := m.(proto.Message)
+	if !ok {
+		return
+	}
+	e := l.WithLevel(payloadLevel)
+	if e == nil {
+		return
+	}
+	if mode == PayloadRedact {
+		p = Redact(p)
+	}
+	data, err := protojson.Marshal(p)
+	if err != nil {
+		e.Err(err).Msg("Failed to marshal message")
+		return
+	}
+	e.RawJSON(key, data).Send()
+}
+
+// Redact returns a copy of m with every string field, including those of
+// nested messages, lists and maps, set to Redacted and every bytes field cleared.
+func Redact(m proto.Message) proto.Message {
+	c := proto.Clone(m)
+	redact(c.ProtoReflect())
+	return c
+}
+
+func redact(m protoreflect.Message) {
+	var fields []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		fields = append(fields, fd)
+		return true
+	})
+	for _, fd := range fields {
+		v := m.Get(fd)
+		switch {
+		case fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				if r, ok := redactValue(fd, list.Get(i)); ok {
+					list.Set(i, r)
+				}
+			}
+		case fd.IsMap():
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				if r, ok := redactValue(fd.MapValue(), mv); ok {
+					v.Map().Set(k, r)
+				}
+				return true
+			})
+		case fd.Kind() == protoreflect.BytesKind:
+			m.Clear(fd)
+		default:
+			if r, ok := redactValue(fd, v); ok {
+				m.Set(fd, r)
+			}
+		}
+	}
+}
+
+// redactValue returns the redacted form of a single value of fd, reporting
+// false when the value was redacted in place or needs no change.
+func redactValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) (protoreflect.Value, bool) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(Redacted), true
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes(nil), true
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		redact(v.Message())
+	}
+	return v, false
+}
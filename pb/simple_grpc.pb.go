@@ -26,8 +26,13 @@ type SimpleClient interface {
 	GetMessage(ctx context.Context, in *Name, opts ...grpc.CallOption) (*Message, error)
 	PutMessage(ctx context.Context, in *Message, opts ...grpc.CallOption) (*Name, error)
 	PingPong(ctx context.Context, in *Message, opts ...grpc.CallOption) (*Message, error)
+	// Over HTTP, messages are sent as newline-delimited JSON, or as server-sent events to clients accepting text/event-stream
 	ListMessage(ctx context.Context, in *Request, opts ...grpc.CallOption) (Simple_ListMessageClient, error)
+	// Over HTTP, messages are uploaded as a JSON array or newline-delimited JSON
 	BulkPutMessage(ctx context.Context, opts ...grpc.CallOption) (Simple_BulkPutMessageClient, error)
+	// Uploaded over HTTP like BulkPutMessage
+	BulkPutMessageV2(ctx context.Context, opts ...grpc.CallOption) (Simple_BulkPutMessageV2Client, error)
+	Chat(ctx context.Context, opts ...grpc.CallOption) (Simple_ChatClient, error)
 }
 
 type simpleClient struct {
@@ -131,6 +136,71 @@ func (x *simpleBulkPutMessageClient) CloseAndRecv() (*emptypb.Empty, error) {
 	return m, nil
 }
 
+func (c *simpleClient) BulkPutMessageV2(ctx context.Context, opts ...grpc.CallOption) (Simple_BulkPutMessageV2Client, error) {
+	stream, err := c.cc.NewStream(ctx, &Simple_ServiceDesc.Streams[2], "/simple.Simple/BulkPutMessageV2", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &simpleBulkPutMessageV2Client{stream}
+	return x, nil
+}
+
+type Simple_BulkPutMessageV2Client interface {
+	Send(*Message) error
+	CloseAndRecv() (*BulkPutSummary, error)
+	grpc.ClientStream
+}
+
+type simpleBulkPutMessageV2Client struct {
+	grpc.ClientStream
+}
+
+func (x *simpleBulkPutMessageV2Client) Send(m *Message) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *simpleBulkPutMessageV2Client) CloseAndRecv() (*BulkPutSummary, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(BulkPutSummary)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *simpleClient) Chat(ctx context.Context, opts ...grpc.CallOption) (Simple_ChatClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Simple_ServiceDesc.Streams[3], "/simple.Simple/Chat", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &simpleChatClient{stream}
+	return x, nil
+}
+
+type Simple_ChatClient interface {
+	Send(*Message) error
+	Recv() (*Message, error)
+	grpc.ClientStream
+}
+
+type simpleChatClient struct {
+	grpc.ClientStream
+}
+
+func (x *simpleChatClient) Send(m *Message) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *simpleChatClient) Recv() (*Message, error) {
+	m := new(Message)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // SimpleServer is the server API for Simple service.
 // All implementations should embed UnimplementedSimpleServer
 // for forward compatibility
@@ -138,8 +208,13 @@ type SimpleServer interface {
 	GetMessage(context.Context, *Name) (*Message, error)
 	PutMessage(context.Context, *Message) (*Name, error)
 	PingPong(context.Context, *Message) (*Message, error)
+	// Over HTTP, messages are sent as newline-delimited JSON, or as server-sent events to clients accepting text/event-stream
 	ListMessage(*Request, Simple_ListMessageServer) error
+	// Over HTTP, messages are uploaded as a JSON array or newline-delimited JSON
 	BulkPutMessage(Simple_BulkPutMessageServer) error
+	// Uploaded over HTTP like BulkPutMessage
+	BulkPutMessageV2(Simple_BulkPutMessageV2Server) error
+	Chat(Simple_ChatServer) error
 }
 
 // UnimplementedSimpleServer should be embedded to have forward compatible implementations.
@@ -161,6 +236,12 @@ func (UnimplementedSimpleServer) ListMessage(*Request, Simple_ListMessageServer)
 func (UnimplementedSimpleServer) BulkPutMessage(Simple_BulkPutMessageServer) error {
 	return status.Errorf(codes.Unimplemented, "method BulkPutMessage not implemented")
 }
+func (UnimplementedSimpleServer) BulkPutMessageV2(Simple_BulkPutMessageV2Server) error {
+	return status.Errorf(codes.Unimplemented, "method BulkPutMessageV2 not implemented")
+}
+func (UnimplementedSimpleServer) Chat(Simple_ChatServer) error {
+	return status.Errorf(codes.Unimplemented, "method Chat not implemented")
+}
 
 // UnsafeSimpleServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to SimpleServer will
@@ -274,6 +355,58 @@ func (x *simpleBulkPutMessageServer) Recv() (*Message, error) {
 	return m, nil
 }
 
+func _Simple_BulkPutMessageV2_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(SimpleServer).BulkPutMessageV2(&simpleBulkPutMessageV2Server{stream})
+}
+
+type Simple_BulkPutMessageV2Server interface {
+	SendAndClose(*BulkPutSummary) error
+	Recv() (*Message, error)
+	grpc.ServerStream
+}
+
+type simpleBulkPutMessageV2Server struct {
+	grpc.ServerStream
+}
+
+func (x *simpleBulkPutMessageV2Server) SendAndClose(m *BulkPutSummary) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *simpleBulkPutMessageV2Server) Recv() (*Message, error) {
+	m := new(Message)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Simple_Chat_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(SimpleServer).Chat(&simpleChatServer{stream})
+}
+
+type Simple_ChatServer interface {
+	Send(*Message) error
+	Recv() (*Message, error)
+	grpc.ServerStream
+}
+
+type simpleChatServer struct {
+	grpc.ServerStream
+}
+
+func (x *simpleChatServer) Send(m *Message) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *simpleChatServer) Recv() (*Message, error) {
+	m := new(Message)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Simple_ServiceDesc is the grpc.ServiceDesc for Simple service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -305,6 +438,17 @@ var Simple_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Simple_BulkPutMessage_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "BulkPutMessageV2",
+			Handler:       _Simple_BulkPutMessageV2_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Chat",
+			Handler:       _Simple_Chat_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "simple.proto",
 }
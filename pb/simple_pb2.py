@@ -13,15 +13,20 @@ _sym_db = _symbol_database.Default()
 
 
 from google.protobuf import empty_pb2 as google_dot_protobuf_dot_empty__pb2
+from google.protobuf import duration_pb2 as google_dot_protobuf_dot_duration__pb2
+import validate_pb2 as validate__pb2
+from google.api import annotations_pb2 as google_dot_api_dot_annotations__pb2
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0csimple.proto\x12\x06simple\x1a\x1bgoogle/protobuf/empty.proto\"6\n\x07Message\x12\x1a\n\x04name\x18\x01 \x01(\x0b\x32\x0c.simple.Name\x12\x0f\n\x07message\x18\x02 \x01(\t\" \n\x04Name\x12\n\n\x02id\x18\x01 \x01(\x05\x12\x0c\n\x04text\x18\x02 \x01(\t\"\x19\n\x07Request\x12\x0e\n\x06number\x18\x01 \x01(\x05\x32\x8a\x02\n\x06Simple\x12-\n\nGetMessage\x12\x0c.simple.Name\x1a\x0f.simple.Message\"\x00\x12-\n\nPutMessage\x12\x0f.simple.Message\x1a\x0c.simple.Name\"\x00\x12.\n\x08PingPong\x12\x0f.simple.Message\x1a\x0f.simple.Message\"\x00\x12\x33\n\x0bListMessage\x12\x0f.simple.Request\x1a\x0f.simple.Message\"\x00\x30\x01\x12=\n\x0e\x42ulkPutMessage\x12\x0f.simple.Message\x1a\x16.google.protobuf.Empty\"\x00(\x01\x42)Z\'github.com/shin5ok/proto-grpc-simple/pbb\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0csimple.proto\x12\x06simple\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1egoogle/protobuf/duration.proto\x1a\x0evalidate.proto\x1a\x1cgoogle/api/annotations.proto\"^\n\x07Message\x12\x1a\n\x04name\x18\x01 \x01(\x0b\x32\x0c.simple.Name\x12\x1a\n\x07message\x18\x02 \x01(\tB\t\xc2\xf3\x18\x05\x1a\x03\x10\x80 \x12\x1b\n\x07payload\x18\x03 \x01(\x0c\x42\n\xc2\xf3\x18\x06\"\x04\x08\x80\x80@\"4\n\x04Name\x12\x14\n\x02id\x18\x01 \x01(\x05\x42\x08\xc2\xf3\x18\x04\x12\x02\x08\x00\x12\x16\n\x04text\x18\x02 \x01(\tB\x08\xc2\xf3\x18\x04\x1a\x02\x10@\"\x81\x02\n\x07Request\x12\x1b\n\x06number\x18\x01 \x01(\x05\x42\x0b\xc2\xf3\x18\x07\x12\x05\x08\x00\x10\x90N\x12:\n\x08interval\x18\x02 \x01(\x0b\x32\x19.google.protobuf.DurationB\r\xc2\xf3\x18\t*\x07\n\x00\x12\x03\x08\x90\x1c\x12\x38\n\x06jitter\x18\x03 \x01(\x0b\x32\x19.google.protobuf.DurationB\r\xc2\xf3\x18\t*\x07\n\x00\x12\x03\x08\x90\x1c\x12?\n\rinitial_delay\x18\x04 \x01(\x0b\x32\x19.google.protobuf.DurationB\r\xc2\xf3\x18\t*\x07\n\x00\x12\x03\x08\x90\x1c\x12\"\n\x0cpayload_size\x18\x05 \x01(\x05\x42\x0c\xc2\xf3\x18\x08\x12\x06\x08\x00\x10\x80\x80@\"w\n\x0e\x42ulkPutSummary\x12\x10\n\x08\x61\x63\x63\x65pted\x18\x01 \x01(\x05\x12\x10\n\x08rejected\x18\x02 \x01(\x05\x12\x1b\n\x05names\x18\x03 \x03(\x0b\x32\x0c.simple.Name\x12$\n\x06\x65rrors\x18\x04 \x03(\x0b\x32\x14.simple.BulkPutError\"<\n\x0c\x42ulkPutError\x12\r\n\x05index\x18\x01 \x01(\x05\x12\x0c\n\x04\x63ode\x18\x02 \x01(\x05\x12\x0f\n\x07message\x18\x03 \x01(\t2\xc7\x04\n\x06Simple\x12\x46\n\nGetMessage\x12\x0c.simple.Name\x1a\x0f.simple.Message\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/messages/{id}\x12Q\n\nPutMessage\x12\x0f.simple.Message\x1a\x0c.simple.Name\"$\xc2\xf3\x18\t\n\x07message\x82\xd3\xe4\x93\x02\x11\"\x0c/v1/messages:\x01*\x12\x41\n\x08PingPong\x12\x0f.simple.Message\x1a\x0f.simple.Message\"\x13\x82\xd3\xe4\x93\x02\r\"\x08/v1/ping:\x01*\x12\x64\n\x0bListMessage\x12\x0f.simple.Request\x1a\x0f.simple.Message\"1\x82\xd3\xe4\x93\x02+\x12\x11/v1/messages:listZ\x16\"\x11/v1/messages:list:\x01*0\x01\x12\\\n\x0e\x42ulkPutMessage\x12\x0f.simple.Message\x1a\x16.google.protobuf.Empty\"\x1f\x82\xd3\xe4\x93\x02\x19\"\x14/v1/messages:bulkPut:\x01*(\x01\x12k\n\x10\x42ulkPutMessageV2\x12\x0f.simple.Message\x1a\x16.simple.BulkPutSummary\",\xc2\xf3\x18\t\n\x07message\x82\xd3\xe4\x93\x02\x19\"\x14/v2/messages:bulkPut:\x01*(\x01\x12.\n\x04\x43hat\x12\x0f.simple.Message\x1a\x0f.simple.Message\"\x00(\x01\x30\x01\x42)Z\'github.com/shin5ok/proto-grpc-simple/pbb\x06proto3')
 
 
 
 _MESSAGE = DESCRIPTOR.message_types_by_name['Message']
 _NAME = DESCRIPTOR.message_types_by_name['Name']
 _REQUEST = DESCRIPTOR.message_types_by_name['Request']
+_BULKPUTSUMMARY = DESCRIPTOR.message_types_by_name['BulkPutSummary']
+_BULKPUTERROR = DESCRIPTOR.message_types_by_name['BulkPutError']
 Message = _reflection.GeneratedProtocolMessageType('Message', (_message.Message,), {
   'DESCRIPTOR' : _MESSAGE,
   '__module__' : 'simple_pb2'
@@ -43,17 +48,65 @@ Request = _reflection.GeneratedProtocolMessageType('Request', (_message.Message,
   })
 _sym_db.RegisterMessage(Request)
 
+BulkPutSummary = _reflection.GeneratedProtocolMessageType('BulkPutSummary', (_message.Message,), {
+  'DESCRIPTOR' : _BULKPUTSUMMARY,
+  '__module__' : 'simple_pb2'
+  # @@protoc_insertion_point(class_scope:simple.BulkPutSummary)
+  })
+_sym_db.RegisterMessage(BulkPutSummary)
+
+BulkPutError = _reflection.GeneratedProtocolMessageType('BulkPutError', (_message.Message,), {
+  'DESCRIPTOR' : _BULKPUTERROR,
+  '__module__' : 'simple_pb2'
+  # @@protoc_insertion_point(class_scope:simple.BulkPutError)
+  })
+_sym_db.RegisterMessage(BulkPutError)
+
 _SIMPLE = DESCRIPTOR.services_by_name['Simple']
 if _descriptor._USE_C_DESCRIPTORS == False:
 
   DESCRIPTOR._options = None
   DESCRIPTOR._serialized_options = b'Z\'github.com/shin5ok/proto-grpc-simple/pb'
-  _MESSAGE._serialized_start=53
-  _MESSAGE._serialized_end=107
-  _NAME._serialized_start=109
-  _NAME._serialized_end=141
-  _REQUEST._serialized_start=143
-  _REQUEST._serialized_end=168
-  _SIMPLE._serialized_start=171
-  _SIMPLE._serialized_end=437
+  _MESSAGE.fields_by_name['message']._options = None
+  _MESSAGE.fields_by_name['message']._serialized_options = b'\xc2\xf3\x18\x05\x1a\x03\x10\x80 '
+  _MESSAGE.fields_by_name['payload']._options = None
+  _MESSAGE.fields_by_name['payload']._serialized_options = b'\xc2\xf3\x18\x06\"\x04\x08\x80\x80@'
+  _NAME.fields_by_name['id']._options = None
+  _NAME.fields_by_name['id']._serialized_options = b'\xc2\xf3\x18\x04\x12\x02\x08\x00'
+  _NAME.fields_by_name['text']._options = None
+  _NAME.fields_by_name['text']._serialized_options = b'\xc2\xf3\x18\x04\x1a\x02\x10@'
+  _REQUEST.fields_by_name['number']._options = None
+  _REQUEST.fields_by_name['number']._serialized_options = b'\xc2\xf3\x18\x07\x12\x05\x08\x00\x10\x90N'
+  _REQUEST.fields_by_name['interval']._options = None
+  _REQUEST.fields_by_name['interval']._serialized_options = b'\xc2\xf3\x18\t*\x07\n\x00\x12\x03\x08\x90\x1c'
+  _REQUEST.fields_by_name['jitter']._options = None
+  _REQUEST.fields_by_name['jitter']._serialized_options = b'\xc2\xf3\x18\t*\x07\n\x00\x12\x03\x08\x90\x1c'
+  _REQUEST.fields_by_name['initial_delay']._options = None
+  _REQUEST.fields_by_name['initial_delay']._serialized_options = b'\xc2\xf3\x18\t*\x07\n\x00\x12\x03\x08\x90\x1c'
+  _REQUEST.fields_by_name['payload_size']._options = None
+  _REQUEST.fields_by_name['payload_size']._serialized_options = b'\xc2\xf3\x18\x08\x12\x06\x08\x00\x10\x80\x80@'
+  _SIMPLE.methods_by_name['GetMessage']._options = None
+  _SIMPLE.methods_by_name['GetMessage']._serialized_options = b'\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/messages/{id}'
+  _SIMPLE.methods_by_name['PutMessage']._options = None
+  _SIMPLE.methods_by_name['PutMessage']._serialized_options = b'\xc2\xf3\x18\t\n\x07message\x82\xd3\xe4\x93\x02\x11\"\x0c/v1/messages:\x01*'
+  _SIMPLE.methods_by_name['PingPong']._options = None
+  _SIMPLE.methods_by_name['PingPong']._serialized_options = b'\x82\xd3\xe4\x93\x02\r\"\x08/v1/ping:\x01*'
+  _SIMPLE.methods_by_name['ListMessage']._options = None
+  _SIMPLE.methods_by_name['ListMessage']._serialized_options = b'\x82\xd3\xe4\x93\x02+\x12\x11/v1/messages:listZ\x16\"\x11/v1/messages:list:\x01*'
+  _SIMPLE.methods_by_name['BulkPutMessage']._options = None
+  _SIMPLE.methods_by_name['BulkPutMessage']._serialized_options = b'\x82\xd3\xe4\x93\x02\x19\"\x14/v1/messages:bulkPut:\x01*'
+  _SIMPLE.methods_by_name['BulkPutMessageV2']._options = None
+  _SIMPLE.methods_by_name['BulkPutMessageV2']._serialized_options = b'\xc2\xf3\x18\t\n\x07message\x82\xd3\xe4\x93\x02\x19\"\x14/v2/messages:bulkPut:\x01*'
+  _MESSAGE._serialized_start=131
+  _MESSAGE._serialized_end=225
+  _NAME._serialized_start=227
+  _NAME._serialized_end=279
+  _REQUEST._serialized_start=282
+  _REQUEST._serialized_end=539
+  _BULKPUTSUMMARY._serialized_start=541
+  _BULKPUTSUMMARY._serialized_end=660
+  _BULKPUTERROR._serialized_start=662
+  _BULKPUTERROR._serialized_end=722
+  _SIMPLE._serialized_start=725
+  _SIMPLE._serialized_end=1308
 # @@protoc_insertion_point(module_scope)
@@ -40,6 +40,16 @@ class SimpleStub(object):
                 request_serializer=simple__pb2.Message.SerializeToString,
                 response_deserializer=google_dot_protobuf_dot_empty__pb2.Empty.FromString,
                 )
+        self.BulkPutMessageV2 = channel.stream_unary(
+                '/simple.Simple/BulkPutMessageV2',
+                request_serializer=simple__pb2.Message.SerializeToString,
+                response_deserializer=simple__pb2.BulkPutSummary.FromString,
+                )
+        self.Chat = channel.stream_stream(
+                '/simple.Simple/Chat',
+                request_serializer=simple__pb2.Message.SerializeToString,
+                response_deserializer=simple__pb2.Message.FromString,
+                )
 
 
 class SimpleServicer(object):
@@ -64,12 +74,27 @@ class SimpleServicer(object):
         raise NotImplementedError('Method not implemented!')
 
     def ListMessage(self, request, context):
-        """Missing associated documentation comment in .proto file."""
+        """Over HTTP, messages are sent as newline-delimited JSON, or as server-sent events to clients accepting text/event-stream
+        """
         context.set_code(grpc.StatusCode.UNIMPLEMENTED)
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
     def BulkPutMessage(self, request_iterator, context):
+        """Over HTTP, messages are uploaded as a JSON array or newline-delimited JSON
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def BulkPutMessageV2(self, request_iterator, context):
+        """Uploaded over HTTP like BulkPutMessage
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def Chat(self, request_iterator, context):
         """Missing associated documentation comment in .proto file."""
         context.set_code(grpc.StatusCode.UNIMPLEMENTED)
         context.set_details('Method not implemented!')
@@ -103,6 +128,16 @@ def add_SimpleServicer_to_server(servicer, server):
                     request_deserializer=simple__pb2.Message.FromString,
                     response_serializer=google_dot_protobuf_dot_empty__pb2.Empty.SerializeToString,
             ),
+            'BulkPutMessageV2': grpc.stream_unary_rpc_method_handler(
+                    servicer.BulkPutMessageV2,
+                    request_deserializer=simple__pb2.Message.FromString,
+                    response_serializer=simple__pb2.BulkPutSummary.SerializeToString,
+            ),
+            'Chat': grpc.stream_stream_rpc_method_handler(
+                    servicer.Chat,
+                    request_deserializer=simple__pb2.Message.FromString,
+                    response_serializer=simple__pb2.Message.SerializeToString,
+            ),
     }
     generic_handler = grpc.method_handlers_generic_handler(
             'simple.Simple', rpc_method_handlers)
@@ -197,3 +232,37 @@ class Simple(object):
             google_dot_protobuf_dot_empty__pb2.Empty.FromString,
             options, channel_credentials,
             insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def BulkPutMessageV2(request_iterator,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.stream_unary(request_iterator, target, '/simple.Simple/BulkPutMessageV2',
+            simple__pb2.Message.SerializeToString,
+            simple__pb2.BulkPutSummary.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def Chat(request_iterator,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.stream_stream(request_iterator, target, '/simple.Simple/Chat',
+            simple__pb2.Message.SerializeToString,
+            simple__pb2.Message.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
@@ -0,0 +1,97 @@
+package rpcerror
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Details is an error status with its details picked apart.
+type Details struct {
+	Code         codes.Code
+	Message      string
+	Info         *errdetails.ErrorInfo
+	Retry        *errdetails.RetryInfo
+	Debug        *errdetails.DebugInfo
+	Request      *errdetails.RequestInfo
+	BadRequest   *errdetails.BadRequest
+	QuotaFailure *errdetails.QuotaFailure
+}
+
+// Decode picks apart the status of err. Details of other types are left out.
+func Decode(err error) *Details {
+	st := status.Convert(err)
+	d := &Details{Code: st.Code(), Message: st.Message()}
+	for _, detail := range st.Details() {
+		switch v := detail.(type) {
+		case *errdetails.ErrorInfo:
+			d.Info = v
+		case *errdetails.RetryInfo:
+			d.Retry = v
+		case *errdetails.DebugInfo:
+			d.Debug = v
+		case *errdetails.RequestInfo:
+			d.Request = v
+		case *errdetails.BadRequest:
+			d.BadRequest = v
+		case *errdetails.QuotaFailure:
+			d.QuotaFailure = v
+		}
+	}
+	return d
+}
+
+// Reason returns the ErrorInfo reason, or "" without one.
+func (d *Details) Reason() string {
+	return d.Info.GetReason()
+}
+
+// RetryDelay returns how long the server asks to wait before retrying, and
+// false when it does not suggest a retry.
+func (d *Details) RetryDelay() (time.Duration, bool) {
+	if d.Retry == nil {
+		return 0, false
+	}
+	return d.Retry.RetryDelay.AsDuration(), true
+}
+
+// String prints the status and its details, one per line.
+func (d *Details) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s", d.Code, d.Message)
+	if d.Info != nil {
+		fmt.Fprintf(&b, "\n  reason: %s (%s)", d.Info.Reason, d.Info.Domain)
+		keys := make([]string, 0, len(d.Info.Metadata))
+		for k := range d.Info.Metadata {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(&b, "\n    %s: %s", k, d.Info.Metadata[k])
+		}
+	}
+	if delay, ok := d.RetryDelay(); ok {
+		fmt.Fprintf(&b, "\n  retry after: %s", delay)
+	}
+	for _, v := range d.BadRequest.GetFieldViolations() {
+		fmt.Fprintf(&b, "\n  field %s: %s", v.Field, v.Description)
+	}
+	for _, v := range d.QuotaFailure.GetViolations() {
+		fmt.Fprintf(&b, "\n  quota %s: %s", v.Subject, v.Description)
+	}
+	if d.Request != nil {
+		fmt.Fprintf(&b, "\n  request id: %s", d.Request.RequestId)
+	}
+	if d.Debug != nil {
+		fmt.Fprintf(&b, "\n  debug: %s", d.Debug.Detail)
+		for _, e := range d.Debug.StackEntries {
+			fmt.Fprintf(&b, "\n    %s", e)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,207 @@
+// Package rpcerror builds the structured errors of the server: a gRPC status
+// with google.rpc.ErrorInfo, and RetryInfo, DebugInfo, RequestInfo or any
+// other error detail where they apply. Decode reads them back on the client.
+package rpcerror
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/runtime/protoiface"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Domain is the ErrorInfo domain of every error raised by the server.
+const Domain = "simple.shin5ok.github.com"
+
+// Reasons of the ErrorInfo of the errors raised by the server. Errors without
+// one of these, such as cancellations, carry the name of their code instead.
+const (
+	ReasonMessageNotFound    = "MESSAGE_NOT_FOUND"
+	ReasonStoreFailed        = "STORE_FAILED"
+	ReasonIDsExhausted       = "IDS_EXHAUSTED"
+	ReasonSendFailed         = "SEND_FAILED"
+	ReasonReceiveFailed      = "RECEIVE_FAILED"
+	ReasonEncodingFailed     = "ENCODING_FAILED"
+	ReasonInvalidRequest     = "INVALID_REQUEST"
+	ReasonInvalidMetadata    = "INVALID_METADATA"
+	ReasonMissingCredentials = "MISSING_CREDENTIALS"
+	ReasonInvalidCredentials = "INVALID_CREDENTIALS"
+	ReasonMissingScope       = "MISSING_SCOPE"
+	ReasonRateLimited        = "RATE_LIMITED"
+	ReasonTooManyStreams     = "TOO_MANY_STREAMS"
+	ReasonTooManyMessages    = "TOO_MANY_STREAM_MESSAGES"
+	ReasonFaultInjected      = "FAULT_INJECTED"
+)
+
+// defaultRetryDelay is suggested for Unavailable and Aborted errors that carry no RetryInfo.
+const defaultRetryDelay = time.Second
+
+type options struct {
+	metadata map[string]string
+	retry    time.Duration
+	cause    error
+	details  []protoiface.MessageV1
+}
+
+type Option func(*options)
+
+// WithMetadata adds key and value pairs to the ErrorInfo.
+func WithMetadata(kv ...string) Option {
+	return func(o *options) {
+		if o.metadata == nil {
+			o.metadata = map[string]string{}
+		}
+		for i := 0; i+1 < len(kv); i += 2 {
+			o.metadata[kv[i]] = kv[i+1]
+		}
+	}
+}
+
+// WithRetry adds RetryInfo telling the client to retry after d.
+func WithRetry(d time.Duration) Option {
+	return func(o *options) { o.retry = d }
+}
+
+// WithCause adds DebugInfo with err and the stack of the caller of New. The
+// interceptors drop it unless debug info is turned on.
+func WithCause(err error) Option {
+	return func(o *options) { o.cause = err }
+}
+
+// WithDetails adds more details, such as BadRequest or QuotaFailure.
+func WithDetails(details ...protoiface.MessageV1) Option {
+	return func(o *options) { o.details = append(o.details, details...) }
+}
+
+// New returns a status error of code with msg, carrying an ErrorInfo with
+// reason, an UPPER_SNAKE_CASE name of what went wrong, and the details the
+// options ask for.
+func New(code codes.Code, reason, msg string, opts ...Option) error {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	details := []protoiface.MessageV1{&errdetails.ErrorInfo{Reason: reason, Domain: Domain, Metadata: o.metadata}}
+	if o.retry > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(o.retry)})
+	}
+	if o.cause != nil {
+		details = append(details, &errdetails.DebugInfo{Detail: o.cause.Error(), StackEntries: stack()})
+	}
+	details = append(details, o.details...)
+
+	st, err := status.New(code, msg).WithDetails(details...)
+	if err != nil {
+		return status.Error(code, msg)
+	}
+	return st.Err()
+}
+
+// stack lists the callers of New.
+func stack() []string {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	var entries []string
+	for {
+		f, more := frames.Next()
+		entries = append(entries, fmt.Sprintf("%s %s:%d", f.Function, f.File, f.Line))
+		if !more {
+			break
+		}
+	}
+	return entries
+}
+
+// Complete brings any error returned by a call into the error model: errors
+// without ErrorInfo get one with the code as reason, Unavailable and Aborted
+// errors suggest a retry, every error gets a RequestInfo with the trace id of
+// ctx, and DebugInfo is dropped unless debug is set.
+func Complete(ctx context.Context, err error, debug bool) error {
+	if err == nil {
+		return nil
+	}
+	st := status.Convert(err)
+	if st.Code() == codes.OK {
+		return err
+	}
+
+	var details []protoiface.MessageV1
+	var hasInfo, hasRetry, hasRequest bool
+	for _, d := range st.Details() {
+		switch d.(type) {
+		case *errdetails.ErrorInfo:
+			hasInfo = true
+		case *errdetails.RetryInfo:
+			hasRetry = true
+		case *errdetails.RequestInfo:
+			hasRequest = true
+		case *errdetails.DebugInfo:
+			if !debug {
+				continue
+			}
+		case error:
+			// A detail that could not be decoded; it cannot be sent on either.
+			continue
+		}
+		if m, ok := d.(protoiface.MessageV1); ok {
+			details = append(details, m)
+		}
+	}
+	if !hasInfo {
+		details = append([]protoiface.MessageV1{&errdetails.ErrorInfo{Reason: CodeName(st.Code()), Domain: Domain}}, details...)
+	}
+	if !hasRetry && (st.Code() == codes.Unavailable || st.Code() == codes.Aborted) {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(defaultRetryDelay)})
+	}
+	if !hasRequest {
+		if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+			details = append(details, &errdetails.RequestInfo{RequestId: sc.TraceID().String()})
+		}
+	}
+
+	completed, derr := status.New(st.Code(), st.Message()).WithDetails(details...)
+	if derr != nil {
+		return err
+	}
+	return completed.Err()
+}
+
+// CodeName returns the name of a code as in google.rpc.Code, e.g.
+// RESOURCE_EXHAUSTED. Errors without a reason of their own get it as reason.
+func CodeName(c codes.Code) string {
+	var b strings.Builder
+	for i, r := range c.String() {
+		if i > 0 && unicode.IsUpper(r) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+// UnaryServerInterceptor completes the errors of unary calls, see Complete.
+func UnaryServerInterceptor(debug bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		return resp, Complete(ctx, err, debug)
+	}
+}
+
+// StreamServerInterceptor completes the errors of streaming calls, see Complete.
+func StreamServerInterceptor(debug bool) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return Complete(ss.Context(), handler(srv, ss), debug)
+	}
+}
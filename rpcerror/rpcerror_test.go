@@ -0,0 +1,105 @@
+package rpcerror
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNew(t *testing.T) {
+	err := New(codes.ResourceExhausted, ReasonRateLimited, "slow down",
+		WithMetadata("method", "/simple.Simple/PingPong"),
+		WithRetry(2*time.Second),
+		WithCause(errors.New("bucket empty")),
+		WithDetails(&errdetails.QuotaFailure{Violations: []*errdetails.QuotaFailure_Violation{{Subject: "rate"}}}))
+
+	d := Decode(err)
+	if d.Code != codes.ResourceExhausted || d.Message != "slow down" {
+		t.Errorf("got %v", d)
+	}
+	if d.Reason() != ReasonRateLimited || d.Info.Domain != Domain || d.Info.Metadata["method"] != "/simple.Simple/PingPong" {
+		t.Errorf("got %v", d.Info)
+	}
+	if delay, ok := d.RetryDelay(); !ok || delay != 2*time.Second {
+		t.Errorf("retry after %s, %v", delay, ok)
+	}
+	if d.Debug.GetDetail() != "bucket empty" || len(d.Debug.StackEntries) == 0 || !strings.Contains(d.Debug.StackEntries[0], "TestNew") {
+		t.Errorf("got %v", d.Debug)
+	}
+	if d.QuotaFailure == nil {
+		t.Error("no QuotaFailure")
+	}
+}
+
+func TestComplete(t *testing.T) {
+	traceID := trace.TraceID{1, 2, 3}
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  trace.SpanID{1},
+	}))
+
+	if Complete(ctx, nil, false) != nil {
+		t.Error("completed a nil error")
+	}
+
+	d := Decode(Complete(ctx, status.Error(codes.Unavailable, "down"), false))
+	if d.Code != codes.Unavailable || d.Message != "down" || d.Reason() != "UNAVAILABLE" || d.Info.Domain != Domain {
+		t.Errorf("got %v", d)
+	}
+	if delay, ok := d.RetryDelay(); !ok || delay != defaultRetryDelay {
+		t.Errorf("retry after %s, %v", delay, ok)
+	}
+	if d.Request.GetRequestId() != traceID.String() {
+		t.Errorf("got request %v", d.Request)
+	}
+
+	cause := New(codes.Internal, ReasonStoreFailed, "failed", WithCause(errors.New("disk full")))
+	if d := Decode(Complete(context.Background(), cause, false)); d.Debug != nil || d.Retry != nil || d.Request != nil || d.Reason() != ReasonStoreFailed {
+		t.Errorf("without debug: got %v", d)
+	}
+	if d := Decode(Complete(context.Background(), cause, true)); d.Debug.GetDetail() != "disk full" {
+		t.Errorf("with debug: got %v", d)
+	}
+
+	retry := New(codes.Aborted, "CONFLICT", "try again", WithRetry(time.Minute))
+	if d := Decode(Complete(ctx, retry, false)); d.Retry.RetryDelay.AsDuration() != time.Minute {
+		t.Errorf("kept retry: got %v", d)
+	}
+	if d := Decode(Complete(ctx, context.Canceled, false)); d.Reason() != "UNKNOWN" {
+		t.Errorf("plain error: got %v", d)
+	}
+}
+
+func TestCodeName(t *testing.T) {
+	for c, want := range map[codes.Code]string{
+		codes.NotFound:           "NOT_FOUND",
+		codes.DeadlineExceeded:   "DEADLINE_EXCEEDED",
+		codes.Unauthenticated:    "UNAUTHENTICATED",
+		codes.FailedPrecondition: "FAILED_PRECONDITION",
+	} {
+		if got := CodeName(c); got != want {
+			t.Errorf("%s: got %q, want %q", c, got, want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	err := New(codes.InvalidArgument, ReasonInvalidRequest, "invalid request",
+		WithMetadata("b", "2", "a", "1"),
+		WithDetails(&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "number", Description: "number must be at most 5"}}}))
+	want := `InvalidArgument: invalid request
+  reason: INVALID_REQUEST (` + Domain + `)
+    a: 1
+    b: 2
+  field number: number must be at most 5`
+	if got := Decode(err).String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	health "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/shin5ok/proto-grpc-simple/auth"
+	"github.com/shin5ok/proto-grpc-simple/config"
+	pb "github.com/shin5ok/proto-grpc-simple/pb"
+)
+
+func TestAuth(t *testing.T) {
+
+	keys := filepath.Join(t.TempDir(), "keys.yaml")
+	os.WriteFile(keys, []byte("[{name: ci, key: secret, scopes: [messages.write]}]"), 0o600)
+	c := config.Default()
+	c.Auth.APIKeysFile = keys
+	c.Auth.Policy = []string{"PingPong=public", "PutMessage=scope:messages.write scope:messages.admin"}
+
+	var subject string
+	interceptor := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if p, ok := auth.FromContext(ctx); ok {
+			subject = p.Subject
+		}
+		return handler(ctx, req)
+	}
+	_, conn, _ := serveConn(t, context.Background(), WithConfig(c), WithUnaryInterceptors(interceptor))
+	client := pb.NewSimpleClient(conn)
+
+	ctx := context.Background()
+	if _, err := client.PingPong(ctx, &pb.Message{}); err != nil {
+		t.Errorf("public method: %v", err)
+	}
+	if _, err := client.GetMessage(ctx, &pb.Name{Id: 1}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("no credentials: got %v", err)
+	}
+	ctx = metadata.AppendToOutgoingContext(ctx, auth.APIKeyKey, "secret")
+	if _, err := client.GetMessage(ctx, &pb.Name{Id: 1}); status.Code(err) == codes.Unauthenticated {
+		t.Errorf("with an API key: got %v", err)
+	}
+	if subject != "ci" {
+		t.Errorf("principal not in the context, got %q", subject)
+	}
+	if _, err := client.PutMessage(ctx, &pb.Message{Message: "foo"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("missing scope: got %v", err)
+	}
+
+	resp, err := health.NewHealthClient(conn).Check(context.Background(), &health.HealthCheckRequest{})
+	if err != nil || resp.Status != health.HealthCheckResponse_SERVING {
+		t.Errorf("health check: %v %v", resp, err)
+	}
+}
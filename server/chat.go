@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shin5ok/proto-grpc-simple/logging"
+	pb "github.com/shin5ok/proto-grpc-simple/pb"
+	"github.com/shin5ok/proto-grpc-simple/rpcerror"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// Chat behavior is selected by request metadata:
+//
+//	x-chat-mode:   echo (default), delay, fanin or fanout
+//	x-chat-delay:  how long delay mode waits before echoing, e.g. "500ms" (default 1s)
+//	x-chat-fanout: how many replies fanout mode sends for each message (default 3)
+//	x-chat-fanin:  how many messages fanin mode merges into one reply (default 3)
+//
+// Fan-out and fan-in are at most maxChatFan.
+const (
+	chatModeKey   = "x-chat-mode"
+	chatDelayKey  = "x-chat-delay"
+	chatFanoutKey = "x-chat-fanout"
+	chatFaninKey  = "x-chat-fanin"
+)
+
+// maxChatFan bounds x-chat-fanout and x-chat-fanin, as neither the
+// validation rules nor the stream limits count fan-out replies.
+const maxChatFan = 100
+
+type chatOption struct {
+	mode   string
+	delay  time.Duration
+	fanout int
+	fanin  int
+}
+
+func chatOptionFromContext(ctx context.Context) (*chatOption, error) {
+	opt := &chatOption{mode: "echo", delay: time.Second, fanout: 3, fanin: 3}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	get := func(key string) string {
+		if v := md.Get(key); len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+
+	if v := get(chatModeKey); v != "" {
+		opt.mode = v
+	}
+	switch opt.mode {
+	case "echo", "delay", "fanin", "fanout":
+	default:
+		return nil, rpcerror.New(codes.InvalidArgument, rpcerror.ReasonInvalidMetadata, fmt.Sprintf("unknown %s: %s", chatModeKey, opt.mode),
+			rpcerror.WithMetadata("key", chatModeKey))
+	}
+
+	if v := get(chatDelayKey); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return nil, rpcerror.New(codes.InvalidArgument, rpcerror.ReasonInvalidMetadata, fmt.Sprintf("invalid %s: %s", chatDelayKey, v),
+				rpcerror.WithMetadata("key", chatDelayKey))
+		}
+		opt.delay = d
+	}
+	for key, p := range map[string]*int{chatFanoutKey: &opt.fanout, chatFaninKey: &opt.fanin} {
+		if v := get(key); v != "" {
+			i, err := strconv.Atoi(v)
+			if err != nil || i < 1 || i > maxChatFan {
+				return nil, rpcerror.New(codes.InvalidArgument, rpcerror.ReasonInvalidMetadata, fmt.Sprintf("invalid %s: %s", key, v),
+					rpcerror.WithMetadata("key", key))
+			}
+			*p = i
+		}
+	}
+	return opt, nil
+}
+
+func (n *newServerImplement) Chat(stream pb.Simple_ChatServer) error {
+	ctx, span := n.tracer.Start(stream.Context(), "chat")
+	defer span.End()
+
+	opt, err := chatOptionFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	logging.Ctx(ctx).
+		Info().
+		Str("Params", fmt.Sprintf("%+v", *opt)).
+		Send()
+
+	var pending []string
+	flush := func() error {
+		if len(pending) == 0 {
+			return nil
+		}
+		message := &pb.Message{Message: strings.Join(pending, "\n")}
+		pending = nil
+		return stream.Send(message)
+	}
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return flush()
+		}
+		if err != nil {
+			return recvError(ctx, err)
+		}
+
+		switch opt.mode {
+		case "echo":
+			err = stream.Send(req)
+		case "delay":
+			if err := wait(ctx, opt.delay); err != nil {
+				return err
+			}
+			err = stream.Send(req)
+		case "fanout":
+			for i := 0; i < opt.fanout && err == nil; i++ {
+				err = stream.Send(&pb.Message{Name: req.Name, Message: fmt.Sprintf("%s %d", req.Message, i)})
+			}
+		case "fanin":
+			pending = append(pending, req.Message)
+			if len(pending) >= opt.fanin {
+				err = flush()
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
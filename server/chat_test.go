@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/shin5ok/proto-grpc-simple/pb"
+)
+
+func chat(t *testing.T, md metadata.MD, messages []string) ([]string, error) {
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+	conn, err := grpc.DialContext(ctx, "localhost", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	stream, err := pb.NewSimpleClient(conn).Chat(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range messages {
+		if err := stream.Send(&pb.Message{Message: m}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stream.CloseSend()
+
+	var results []string
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return results, nil
+		}
+		if err != nil {
+			return results, err
+		}
+		results = append(results, resp.Message)
+	}
+}
+
+func TestChat(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		md       metadata.MD
+		messages []string
+		expected []string
+	}{
+		{"echo", metadata.Pairs(), []string{"foo", "bar"}, []string{"foo", "bar"}},
+		{"delay", metadata.Pairs(chatModeKey, "delay", chatDelayKey, "10ms"), []string{"foo", "bar"}, []string{"foo", "bar"}},
+		{"fanout", metadata.Pairs(chatModeKey, "fanout", chatFanoutKey, "2"), []string{"foo", "bar"}, []string{"foo 0", "foo 1", "bar 0", "bar 1"}},
+		{"fanin", metadata.Pairs(chatModeKey, "fanin", chatFaninKey, "2"), []string{"foo", "bar", "baz"}, []string{"foo\nbar", "baz"}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			start := time.Now()
+			results, err := chat(t, c.md, c.messages)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != len(c.expected) {
+				t.Fatalf("%q is not %q", results, c.expected)
+			}
+			for i := range results {
+				if results[i] != c.expected[i] {
+					t.Errorf("%q is not %q", results[i], c.expected[i])
+				}
+			}
+			if c.name == "delay" && time.Since(start) < 20*time.Millisecond {
+				t.Errorf("messages were not delayed")
+			}
+		})
+	}
+}
+
+func TestChatInvalidMode(t *testing.T) {
+	_, err := chat(t, metadata.Pairs(chatModeKey, "unknown"), []string{"foo"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestChatFanLimit(t *testing.T) {
+	for _, md := range []metadata.MD{
+		metadata.Pairs(chatModeKey, "fanout", chatFanoutKey, "2147483647"),
+		metadata.Pairs(chatModeKey, "fanin", chatFaninKey, "101"),
+	} {
+		if _, err := chat(t, md, []string{"foo"}); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%v: expected InvalidArgument, got %v", md, err)
+		}
+	}
+	results, err := chat(t, metadata.Pairs(chatModeKey, "fanout", chatFanoutKey, "100"), []string{"foo"})
+	if err != nil || len(results) != maxChatFan {
+		t.Errorf("at the limit: got %d replies, %v", len(results), err)
+	}
+}
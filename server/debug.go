@@ -0,0 +1,33 @@
+package server
+
+import (
+	"net/http"
+	"net/http/pprof"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/shin5ok/proto-grpc-simple/config"
+)
+
+// handleDebug adds the /debug endpoints c turns on to mux.
+func handleDebug(mux *http.ServeMux, c config.Config) {
+	if c.Debug.Pprof {
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	}
+	if c.Debug.Config {
+		// Configuration is only read at startup, so it can be rendered once.
+		b, err := yaml.Marshal(c.Redacted())
+		mux.HandleFunc("/debug/config", func(w http.ResponseWriter, r *http.Request) {
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.Write(b)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	channelz "google.golang.org/grpc/channelz/grpc_channelz_v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/shin5ok/proto-grpc-simple/config"
+)
+
+func TestDebug(t *testing.T) {
+	c := config.Default()
+	c.Debug = config.Debug{Admin: true, Pprof: true, Config: true}
+	s, conn, _ := serveConn(t, context.Background(), WithConfig(c))
+
+	servers, err := channelz.NewChannelzClient(conn).GetServers(context.Background(), &channelz.GetServersRequest{})
+	if err != nil || len(servers.Server) == 0 {
+		t.Errorf("channelz: %v %v", servers, err)
+	}
+	for path, want := range map[string]string{
+		"/debug/pprof/":          "goroutine",
+		"/debug/pprof/goroutine": "",
+		"/debug/config":          "pprof: true",
+	} {
+		w := httptest.NewRecorder()
+		s.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), want) {
+			t.Errorf("%s: %d %.100s", path, w.Code, w.Body)
+		}
+	}
+
+	s, conn, _ = serveConn(t, context.Background())
+	if _, err := channelz.NewChannelzClient(conn).GetServers(context.Background(), &channelz.GetServersRequest{}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("channelz when off: %v", err)
+	}
+	for _, path := range []string{"/debug/pprof/", "/debug/config"} {
+		w := httptest.NewRecorder()
+		s.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("%s when off: %d", path, w.Code)
+		}
+	}
+}
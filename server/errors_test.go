@@ -0,0 +1,47 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/shin5ok/proto-grpc-simple/config"
+	pb "github.com/shin5ok/proto-grpc-simple/pb"
+	"github.com/shin5ok/proto-grpc-simple/rpcerror"
+	"github.com/shin5ok/proto-grpc-simple/store"
+)
+
+func TestErrorDetails(t *testing.T) {
+
+	ctx := context.Background()
+	for _, debug := range []bool{false, true} {
+		c := config.Default()
+		c.DebugErrors = debug
+		c.FaultInjection = true
+		_, client, _ := serve(t, ctx, WithConfig(c), WithStore(failingStore{store.NewMemory()}))
+
+		_, err := client.GetMessage(ctx, &pb.Name{Id: 7, Text: "not stored"})
+		d := rpcerror.Decode(err)
+		if d.Code != codes.NotFound || d.Reason() != rpcerror.ReasonMessageNotFound || d.Info.Metadata["id"] != "7" {
+			t.Errorf("not found: got %v", d)
+		}
+
+		_, err = client.PutMessage(ctx, &pb.Message{Message: "fail"})
+		d = rpcerror.Decode(err)
+		if d.Code != codes.Internal || d.Reason() != rpcerror.ReasonStoreFailed || strings.Contains(d.Message, "failed to store") {
+			t.Errorf("store failure: got %v", d)
+		}
+		if (d.Debug != nil) != debug {
+			t.Errorf("debug %v: got %v", debug, d.Debug)
+		}
+
+		_, err = client.PingPong(metadata.AppendToOutgoingContext(ctx, "x-fault-code", "UNAVAILABLE"), &pb.Message{})
+		d = rpcerror.Decode(err)
+		if _, ok := d.RetryDelay(); d.Code != codes.Unavailable || d.Reason() != rpcerror.ReasonFaultInjected || !ok {
+			t.Errorf("injected fault: got %v", d)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/shin5ok/proto-grpc-simple/certgen"
+	"github.com/shin5ok/proto-grpc-simple/config"
+	pb "github.com/shin5ok/proto-grpc-simple/pb"
+	"github.com/shin5ok/proto-grpc-simple/rpcerror"
+)
+
+func TestGateway(t *testing.T) {
+
+	c := config.Default()
+	c.Limits.Methods = []string{"PingPong=1/1"}
+	c.Validation.Enabled = true
+	s, _, _ := serve(t, context.Background(), WithConfig(c))
+	ts := httptest.NewServer(s.Gateway())
+	defer ts.Close()
+
+	call := func(method, path, accept, body string) (*http.Response, string) {
+		t.Helper()
+		req, _ := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		return resp, string(b)
+	}
+
+	resp, body := call("POST", "/v1/messages", "", `{"message":"hello"}`)
+	name := &pb.Name{}
+	if resp.StatusCode != http.StatusOK || protojson.Unmarshal([]byte(body), name) != nil {
+		t.Fatalf("put: %d %s", resp.StatusCode, body)
+	}
+	if resp, body := call("GET", fmt.Sprintf("/v1/messages/%d", name.Id), "", ""); resp.StatusCode != http.StatusOK || !strings.Contains(body, "hello") {
+		t.Errorf("get: %d %s", resp.StatusCode, body)
+	}
+	if resp, body := call("GET", "/v1/messages/0?text=nothing", "", ""); resp.StatusCode != http.StatusNotFound || !strings.Contains(body, rpcerror.ReasonMessageNotFound) {
+		t.Errorf("get missing: %d %s", resp.StatusCode, body)
+	}
+
+	// protojson may put spaces between fields.
+	if _, body := call("GET", "/v1/messages:list?number=2&interval=0s", "", ""); strings.Count(body, "\n") != 2 || strings.ReplaceAll(body, " ", "") != "{\"result\":{\"message\":\"send0\"}}\n{\"result\":{\"message\":\"send1\"}}\n" {
+		t.Errorf("list as ndjson: %q", body)
+	}
+	if resp, body := call("POST", "/v1/messages:list", "text/event-stream", `{"number":1,"interval":"0s"}`); resp.Header.Get("Content-Type") != "text/event-stream" || !strings.HasPrefix(body, "data: {") {
+		t.Errorf("list as events: %q", body)
+	}
+	if resp, _ := call("GET", "/v1/messages:list?number=100000", "", ""); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("list too many: %d", resp.StatusCode)
+	}
+
+	if resp, body := call("POST", "/v2/messages:bulkPut", "", `{"message":"a"} {"message":"b"}`); resp.StatusCode != http.StatusOK || !strings.Contains(body, `"accepted":2`) {
+		t.Errorf("bulk put v2: %d %s", resp.StatusCode, body)
+	}
+	if resp, body := call("POST", "/v1/messages:bulkPut", "", "{\"message\":\"a\"}\n{\"message\":\"b\"}\n"); resp.StatusCode != http.StatusOK || body != "{}" {
+		t.Errorf("bulk put ndjson: %d %s", resp.StatusCode, body)
+	}
+	if resp, _ := call("POST", "/v1/messages:bulkPut", "", `{"message":"a"} {"message":`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("bulk put truncated: %d", resp.StatusCode)
+	}
+
+	call("POST", "/v1/ping", "", "{}")
+	if resp, _ := call("POST", "/v1/ping", "", "{}"); resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") == "" {
+		t.Errorf("rate limited: %d %v", resp.StatusCode, resp.Header)
+	}
+	if resp, _ := call("DELETE", "/v1/ping", "", ""); resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("wrong method: %d", resp.StatusCode)
+	}
+}
+
+func TestGatewayTLS(t *testing.T) {
+
+	dir := t.TempDir()
+	ca, _ := certgen.NewCA(certgen.Options{CommonName: "ca"})
+	serverCert, _ := ca.Issue(certgen.Options{CommonName: "localhost", Hosts: []string{"127.0.0.1"}})
+	clientCert, _ := ca.Issue(certgen.Options{CommonName: "client", Client: true})
+	c := config.Default()
+	c.TLS.CertFile, c.TLS.KeyFile = filepath.Join(dir, "server.pem"), filepath.Join(dir, "server-key.pem")
+	c.TLS.ClientCAFile = filepath.Join(dir, "ca.pem")
+	if err := serverCert.Write(c.TLS.CertFile, c.TLS.KeyFile); err != nil {
+		t.Fatal(err)
+	}
+	if err := ca.Write(c.TLS.ClientCAFile, filepath.Join(dir, "ca-key.pem")); err != nil {
+		t.Fatal(err)
+	}
+
+	gatewayLis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	serve(t, context.Background(), WithConfig(c), WithGatewayListener(gatewayLis))
+	url := "://" + gatewayLis.Addr().String() + "/v1/ping"
+
+	if resp, err := http.Post("http"+url, "application/json", strings.NewReader("{}")); err == nil {
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			t.Error("plaintext call got through")
+		}
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Cert)
+	call := func(certs ...tls.Certificate) error {
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots, Certificates: certs}}}
+		resp, err := client.Post("https"+url, "application/json", strings.NewReader("{}"))
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("status %d", resp.StatusCode)
+		}
+		return nil
+	}
+	if err := call(); err == nil {
+		t.Error("call without a client certificate got through")
+	}
+	if err := call(clientCert.TLSCertificate()); err != nil {
+		t.Errorf("call with a client certificate: %v", err)
+	}
+}
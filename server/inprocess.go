@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"math"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/shin5ok/proto-grpc-simple/gateway"
+)
+
+// The gateway calls the server over an in-memory connection, so its calls
+// pass the whole interceptor chain without leaving the process.
+const inProcessBufSize = 1 << 20
+
+// inProcessAddr is the address of both ends of in-process connections.
+type inProcessAddr struct{}
+
+func (inProcessAddr) Network() string { return "inprocess" }
+func (inProcessAddr) String() string  { return "inprocess" }
+
+// inProcessListener hands out connections that can be told apart from those
+// of any other listener, bufconn ones included.
+type inProcessListener struct {
+	*bufconn.Listener
+}
+
+type inProcessConn struct {
+	net.Conn
+}
+
+func (c inProcessConn) LocalAddr() net.Addr  { return inProcessAddr{} }
+func (c inProcessConn) RemoteAddr() net.Addr { return inProcessAddr{} }
+
+func (l inProcessListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return inProcessConn{c}, nil
+}
+
+func (l inProcessListener) Addr() net.Addr { return inProcessAddr{} }
+
+// dial opens a client connection to l.
+func (l inProcessListener) dial() (*grpc.ClientConn, error) {
+	return grpc.Dial("passthrough:///inprocess",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return l.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		// The server's own send limit is the one that counts.
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(math.MaxInt32)),
+	)
+}
+
+// withForwardedPeer replaces the peer of in-process calls by the HTTP client
+// the gateway forwards them for, so per-client limits and logs see that
+// client. Other calls cannot set their peer this way.
+func withForwardedPeer(ctx context.Context) context.Context {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr != (inProcessAddr{}) {
+		return ctx
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	v := md.Get(gateway.ForwardedForKey)
+	if len(v) == 0 || v[0] == "" {
+		return ctx
+	}
+	forwarded := *p
+	forwarded.Addr = forwardedAddr(v[0])
+	return peer.NewContext(ctx, &forwarded)
+}
+
+// forwardedAddr is the address of the HTTP client of a gateway call.
+type forwardedAddr string
+
+func (a forwardedAddr) Network() string { return "gateway" }
+func (a forwardedAddr) String() string  { return string(a) }
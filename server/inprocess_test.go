@@ -0,0 +1,51 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shin5ok/proto-grpc-simple/config"
+)
+
+// The front ends share the in-process connection, so the per-connection
+// limits must not apply to it.
+func TestInProcessTransport(t *testing.T) {
+	c := config.Default()
+	c.GRPC.MaxConcurrentStreams = 1
+	c.GRPC.MaxConnectionAge = 50 * time.Millisecond
+	c.GRPC.MaxConnectionAgeGrace = 50 * time.Millisecond
+	s, _, _ := serve(t, context.Background(), WithConfig(c))
+	ts := httptest.NewServer(s.Gateway())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/v1/messages:list?number=2&interval=0.5s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	r := bufio.NewReader(resp.Body)
+	if _, err := r.ReadString('\n'); err != nil {
+		t.Fatal(err)
+	}
+
+	client := &http.Client{Timeout: 250 * time.Millisecond}
+	put, err := client.Post(ts.URL+"/v1/messages", "application/json", strings.NewReader(`{"message":"beside"}`))
+	if err != nil {
+		t.Fatalf("second call beside a stream: %v", err)
+	}
+	put.Body.Close()
+	if put.StatusCode != http.StatusOK {
+		t.Errorf("second call beside a stream: %d", put.StatusCode)
+	}
+
+	rest, err := io.ReadAll(r)
+	if err != nil || strings.Count(string(rest), "\n") != 1 || strings.Contains(string(rest), "error") {
+		t.Errorf("stream past max_connection_age: %q %v", rest, err)
+	}
+}
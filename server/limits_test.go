@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/shin5ok/proto-grpc-simple/config"
+	pb "github.com/shin5ok/proto-grpc-simple/pb"
+	"github.com/shin5ok/proto-grpc-simple/rpcerror"
+)
+
+func TestLimits(t *testing.T) {
+
+	c := config.Default()
+	c.Limits.Methods = []string{"PingPong=1/2"}
+	c.Limits.MaxStreamMessages = 2
+	_, client, _ := serve(t, context.Background(), WithConfig(c))
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.PingPong(ctx, &pb.Message{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err := client.PingPong(ctx, &pb.Message{})
+	if d := rpcerror.Decode(err); d.Code != codes.ResourceExhausted || d.Reason() != rpcerror.ReasonRateLimited || d.Retry == nil || d.QuotaFailure == nil {
+		t.Errorf("third call: got %v", d)
+	}
+	if _, err := client.PutMessage(ctx, &pb.Message{Message: "foo"}); err != nil {
+		t.Errorf("unlimited method: %v", err)
+	}
+
+	stream, err := client.ListMessage(ctx, &pb.Request{Number: 3, Interval: durationpb.New(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	received := 0
+	for {
+		if _, err = stream.Recv(); err != nil {
+			break
+		}
+		received++
+	}
+	if received != 2 || status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("received %d messages, then %v", received, err)
+	}
+}
+
+func TestLimitsBeforeAuth(t *testing.T) {
+
+	keys := filepath.Join(t.TempDir(), "keys.yaml")
+	os.WriteFile(keys, []byte("[{name: ci, key: secret}]"), 0o600)
+	c := config.Default()
+	c.Auth.APIKeysFile = keys
+	c.Limits.Methods = []string{"GetMessage=1/1"}
+	_, client, _ := serve(t, context.Background(), WithConfig(c))
+	ctx := context.Background()
+
+	if _, err := client.GetMessage(ctx, &pb.Name{Id: 1}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("first call: got %v", err)
+	}
+	if _, err := client.GetMessage(ctx, &pb.Name{Id: 1}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("unauthenticated calls are not limited: got %v", err)
+	}
+
+	// Limited by principal, callers are only told apart once authenticated.
+	c.Limits.Key = "principal"
+	_, client, _ = serve(t, context.Background(), WithConfig(c))
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetMessage(ctx, &pb.Name{Id: 1}); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("call %d: got %v", i, err)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const instrumentationName = "github.com/shin5ok/proto-grpc-simple"
+
+// instruments are the application and per-RPC metrics of one Server.
+type instruments struct {
+	messagesStored       metric.Int64Counter
+	listMessageSent      metric.Int64Counter
+	listMessageDelivered metric.Int64Histogram
+	bulkPutBatchSize     metric.Int64Histogram
+
+	rpcRequestsPerRPC  metric.Int64Histogram
+	rpcResponsesPerRPC metric.Int64Histogram
+	// streamDuration is not an instrument of the conventions: otelgrpc
+	// records rpc.server.duration for unary calls only.
+	streamDuration metric.Float64Histogram
+}
+
+// newInstruments creates the instruments from mp. Instrument errors are
+// dropped because the SDK still returns a usable instrument along with them.
+func newInstruments(mp metric.MeterProvider) *instruments {
+	meter := mp.Meter(instrumentationName)
+	m := &instruments{}
+
+	m.messagesStored, _ = meter.Int64Counter("simple.messages.stored",
+		metric.WithDescription("Messages written to the message store."))
+	m.listMessageSent, _ = meter.Int64Counter("simple.list_message.sent",
+		metric.WithDescription("Messages sent by ListMessage."))
+	m.listMessageDelivered, _ = meter.Int64Histogram("simple.list_message.delivered",
+		metric.WithDescription("Messages delivered by ListMessage before the stream finished or was cancelled."))
+	m.bulkPutBatchSize, _ = meter.Int64Histogram("simple.bulk_put.batch_size",
+		metric.WithDescription("Messages received in one BulkPutMessage call."))
+
+	m.rpcRequestsPerRPC, _ = meter.Int64Histogram("rpc.server.requests_per_rpc",
+		metric.WithDescription("Messages received per RPC."))
+	m.rpcResponsesPerRPC, _ = meter.Int64Histogram("rpc.server.responses_per_rpc",
+		metric.WithDescription("Messages sent per RPC."))
+	m.streamDuration, _ = meter.Float64Histogram("simple.rpc.stream.duration",
+		metric.WithDescription("Duration of streaming RPCs."), metric.WithUnit("ms"))
+	return m
+}
+
+// detach keeps the span of ctx but drops its cancellation, because the SDK
+// ignores measurements recorded with a context that is already done.
+func detach(ctx context.Context) context.Context {
+	return trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx))
+}
+
+func codeAttr(err error) attribute.KeyValue {
+	return attribute.Int64("rpc.grpc.status_code", int64(status.Code(err)))
+}
+
+func rpcAttrs(fullMethod string, err error) metric.MeasurementOption {
+	service, method := splitMethod(fullMethod)
+	return metric.WithAttributes(
+		attribute.String("rpc.system", "grpc"),
+		attribute.String("rpc.service", service),
+		attribute.String("rpc.method", method),
+		codeAttr(err),
+	)
+}
+
+func splitMethod(fullMethod string) (string, string) {
+	for i := len(fullMethod) - 1; i > 0; i-- {
+		if fullMethod[i] == '/' {
+			return fullMethod[1:i], fullMethod[i+1:]
+		}
+	}
+	return "", fullMethod
+}
+
+// The unary duration comes from otelgrpc; these add the message counts of the
+// RPC semantic conventions and a duration for streams, which otelgrpc leaves out.
+func (m *instruments) unaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	ctx = detach(ctx)
+	attrs := rpcAttrs(info.FullMethod, err)
+	m.rpcRequestsPerRPC.Record(ctx, 1, attrs)
+	if err == nil {
+		m.rpcResponsesPerRPC.Record(ctx, 1, attrs)
+	} else {
+		m.rpcResponsesPerRPC.Record(ctx, 0, attrs)
+	}
+	return resp, err
+}
+
+func (m *instruments) streamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	s := &countingStream{ServerStream: ss}
+	err := handler(srv, s)
+
+	ctx := detach(ss.Context())
+	attrs := rpcAttrs(info.FullMethod, err)
+	m.rpcRequestsPerRPC.Record(ctx, s.received, attrs)
+	m.rpcResponsesPerRPC.Record(ctx, s.sent, attrs)
+	m.streamDuration.Record(ctx, float64(time.Since(start))/float64(time.Millisecond), attrs)
+	return err
+}
+
+type countingStream struct {
+	grpc.ServerStream
+	sent     int64
+	received int64
+}
+
+func (s *countingStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent++
+	}
+	return err
+}
+
+func (s *countingStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.received++
+	}
+	return err
+}
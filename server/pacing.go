@@ -0,0 +1,80 @@
+package server
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	pb "github.com/shin5ok/proto-grpc-simple/pb"
+	"github.com/shin5ok/proto-grpc-simple/rpcerror"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+// maxPayloadSize bounds payload_size, which is allocated for every message,
+// whether or not requests are validated.
+const maxPayloadSize = 1 << 20
+
+// pacing controls how ListMessage spreads its messages over time.
+type pacing struct {
+	initialDelay time.Duration
+	interval     time.Duration
+	jitter       time.Duration
+	payloadSize  int
+}
+
+// pacingFromRequest takes the pacing from the request, falling back to interval.
+func pacingFromRequest(req *pb.Request, interval time.Duration) (*pacing, error) {
+	p := &pacing{
+		initialDelay: req.InitialDelay.AsDuration(),
+		interval:     interval,
+		jitter:       req.Jitter.AsDuration(),
+		payloadSize:  int(req.PayloadSize),
+	}
+	if req.Interval != nil {
+		p.interval = req.Interval.AsDuration()
+	}
+
+	for field, d := range map[string]time.Duration{
+		"interval":      p.interval,
+		"jitter":        p.jitter,
+		"initial_delay": p.initialDelay,
+	} {
+		if d < 0 {
+			return nil, invalidField(field, fmt.Sprintf("%s must not be negative: %s", field, d))
+		}
+	}
+	if p.payloadSize < 0 {
+		return nil, invalidField("payload_size", fmt.Sprintf("payload_size must not be negative: %d", p.payloadSize))
+	}
+	if p.payloadSize > maxPayloadSize {
+		return nil, invalidField("payload_size", fmt.Sprintf("payload_size must be at most %d: %d", maxPayloadSize, p.payloadSize))
+	}
+	return p, nil
+}
+
+// next returns the wait before the following message, the interval moved by up to ±jitter.
+func (p *pacing) next() time.Duration {
+	d := p.interval
+	if p.jitter > 0 {
+		d += time.Duration(rand.Int63n(int64(2*p.jitter)+1)) - p.jitter
+	}
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+func (p *pacing) payload() []byte {
+	if p.payloadSize == 0 {
+		return nil
+	}
+	b := make([]byte, p.payloadSize)
+	rand.Read(b)
+	return b
+}
+
+func invalidField(field, description string) error {
+	return rpcerror.New(codes.InvalidArgument, rpcerror.ReasonInvalidRequest, description,
+		rpcerror.WithDetails(&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: field, Description: description}}}))
+}
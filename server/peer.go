@@ -0,0 +1,49 @@
+package server
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"github.com/shin5ok/proto-grpc-simple/logging"
+)
+
+// peerSubject returns the subject of the client certificate of the call, if any.
+func peerSubject(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.PeerCertificates) == 0 {
+		return ""
+	}
+	return info.State.PeerCertificates[0].Subject.String()
+}
+
+// withPeer adds the client certificate subject to the span and request logger of ctx.
+func withPeer(ctx context.Context) context.Context {
+	subject := peerSubject(ctx)
+	if subject == "" {
+		return ctx
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("tls.client.subject", subject))
+	l := logging.Ctx(ctx).With().Str("tls.client.subject", subject).Logger()
+	return logging.NewContext(ctx, l)
+}
+
+func peerUnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(withPeer(withForwardedPeer(ctx)), req)
+}
+
+func peerStreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx := withPeer(withForwardedPeer(ss.Context()))
+	if ctx == ss.Context() {
+		return handler(srv, ss)
+	}
+	return handler(srv, logging.StreamWithContext(ctx, ss))
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"github.com/shin5ok/proto-grpc-simple/logging"
+)
+
+func TestWithPeer(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := logging.NewContext(context.Background(), zerolog.New(&buf))
+
+	if withPeer(ctx) != ctx {
+		t.Error("context changed without a peer")
+	}
+
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "client", Organization: []string{"simple"}}}
+	ctx = peer.NewContext(ctx, &peer.Peer{AuthInfo: credentials.TLSInfo{
+		State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}},
+	}})
+	logging.Ctx(withPeer(ctx)).Info().Send()
+	if !strings.Contains(buf.String(), `"tls.client.subject":"CN=client,O=simple"`) {
+		t.Errorf("got %s", buf.String())
+	}
+}
@@ -0,0 +1,590 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/pereslava/grpc_zerolog"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/admin"
+	"google.golang.org/grpc/credentials"
+	health "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/shin5ok/proto-grpc-simple/auth"
+	"github.com/shin5ok/proto-grpc-simple/config"
+	"github.com/shin5ok/proto-grpc-simple/fault"
+	"github.com/shin5ok/proto-grpc-simple/gateway"
+	"github.com/shin5ok/proto-grpc-simple/healthcheck"
+	"github.com/shin5ok/proto-grpc-simple/ids"
+	"github.com/shin5ok/proto-grpc-simple/limiter"
+	"github.com/shin5ok/proto-grpc-simple/logging"
+	pb "github.com/shin5ok/proto-grpc-simple/pb"
+	"github.com/shin5ok/proto-grpc-simple/rpcerror"
+	"github.com/shin5ok/proto-grpc-simple/store"
+	"github.com/shin5ok/proto-grpc-simple/tlsconfig"
+	"github.com/shin5ok/proto-grpc-simple/validate"
+	"github.com/shin5ok/proto-grpc-simple/web"
+)
+
+// Server is the Simple service together with its health check, interceptor
+// chain, the HTTP endpoints for /metrics and /admin/health, the HTTP/JSON
+// gateway and gRPC-Web and Connect.
+type Server struct {
+	config     config.Config
+	logger     zerolog.Logger
+	tp         trace.TracerProvider
+	mp         metric.MeterProvider
+	store      store.Store
+	ownStore   bool
+	ids        ids.Allocator
+	unary      []grpc.UnaryServerInterceptor
+	stream     []grpc.StreamServerInterceptor
+	serverOpts []grpc.ServerOption
+	metricsLis net.Listener
+	gatewayLis net.Listener
+
+	grpc          *grpc.Server
+	inProcessGRPC *grpc.Server
+	health        *healthcheck.Server
+	http          *http.Server
+	inProcess     inProcessListener
+	inProcessConn *grpc.ClientConn
+	gateway       *http.Server
+	webTLS        *tls.Config
+	adminCleanup  func()
+
+	// mainHTTP serves the main port in single-port mode or with gRPC-Web and
+	// Connect on, handing gRPC calls to grpc, which httpCalls counts.
+	mainHTTP  *http.Server
+	httpCalls inFlight
+
+	shutdownOnce sync.Once
+	shutdownErr  error
+}
+
+type Option func(*Server)
+
+// WithConfig sets the configuration; without it New uses config.Default.
+func WithConfig(c config.Config) Option {
+	return func(s *Server) { s.config = c }
+}
+
+// WithLogger sets the logger for the interceptors and handlers; without it New uses the global one.
+func WithLogger(l zerolog.Logger) Option {
+	return func(s *Server) { s.logger = l }
+}
+
+// WithTracerProvider sets where spans go; without it New uses the global provider.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(s *Server) { s.tp = tp }
+}
+
+// WithMeterProvider sets where metrics go; without it New uses the global provider.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(s *Server) { s.mp = mp }
+}
+
+// WithStore sets the message store. The caller keeps ownership and closes it;
+// without it New opens the store selected by the configuration and Shutdown closes it.
+func WithStore(st store.Store) Option {
+	return func(s *Server) { s.store = st }
+}
+
+// WithIDs sets the id allocator; without it New creates the one selected by the configuration.
+func WithIDs(a ids.Allocator) Option {
+	return func(s *Server) { s.ids = a }
+}
+
+// WithUnaryInterceptors adds interceptors after the built-in ones, so they
+// see the request logger and span in the context.
+func WithUnaryInterceptors(i ...grpc.UnaryServerInterceptor) Option {
+	return func(s *Server) { s.unary = append(s.unary, i...) }
+}
+
+// WithStreamInterceptors is the streaming counterpart of WithUnaryInterceptors.
+func WithStreamInterceptors(i ...grpc.StreamServerInterceptor) Option {
+	return func(s *Server) { s.stream = append(s.stream, i...) }
+}
+
+// WithServerOptions passes extra options to grpc.NewServer, for both the
+// gRPC server and the in-process one the HTTP front ends call.
+func WithServerOptions(opts ...grpc.ServerOption) Option {
+	return func(s *Server) { s.serverOpts = append(s.serverOpts, opts...) }
+}
+
+// WithMetricsListener makes Serve also serve Handler on l.
+func WithMetricsListener(l net.Listener) Option {
+	return func(s *Server) { s.metricsLis = l }
+}
+
+// WithGatewayListener makes Serve also serve Gateway on l, over TLS when gRPC is.
+func WithGatewayListener(l net.Listener) Option {
+	return func(s *Server) { s.gatewayLis = l }
+}
+
+// New builds a Server from the options. Nothing is served until Serve.
+func New(opts ...Option) (_ *Server, err error) {
+	s := &Server{
+		config: config.Default(),
+		logger: log.Logger,
+		tp:     otel.GetTracerProvider(),
+		mp:     otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	c := s.config
+	// One cleanup for every way New can fail, whatever it got to build.
+	defer func() {
+		if err != nil {
+			s.abandon()
+		}
+	}()
+
+	payloadMode, err := logging.ParsePayloadMode(c.Log.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var creds credentials.TransportCredentials
+	if c.TLS.CertFile != "" {
+		clientAuth, err := tlsconfig.ParseClientAuth(c.TLS.ClientAuth)
+		if err != nil {
+			return nil, err
+		}
+		r, err := tlsconfig.New(tlsconfig.Files{
+			CertFile:     c.TLS.CertFile,
+			KeyFile:      c.TLS.KeyFile,
+			ClientCAFile: c.TLS.ClientCAFile,
+			ClientAuth:   clientAuth,
+		}, s.logger)
+		if err != nil {
+			return nil, err
+		}
+		creds = credentials.NewTLS(r.Config())
+		// The HTTP front ends reach gRPC in-process, past its TLS handshake,
+		// so they do the handshake, client certificates included, themselves.
+		s.webTLS = webTLSConfig(r.Config())
+	}
+
+	var authenticator *auth.Authenticator
+	if c.Auth.Enabled() {
+		// Probes carry no credentials.
+		policy := append([]string{"/" + health.Health_ServiceDesc.ServiceName + "/=public"}, c.Auth.Policy...)
+		p, err := auth.ParsePolicy(pb.Simple_ServiceDesc.ServiceName, policy, c.Auth.DefaultPolicy)
+		if err != nil {
+			return nil, err
+		}
+		authenticator, err = auth.New(auth.Options{
+			JWKSFile:    c.Auth.JWKSFile,
+			HMACKeyFile: c.Auth.HMACKeyFile,
+			Issuer:      c.Auth.Issuer,
+			Audience:    c.Auth.Audience,
+			APIKeysFile: c.Auth.APIKeysFile,
+			Policy:      p,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var lim *limiter.Limiter
+	if c.Limits.Enabled() {
+		methods, err := limiter.ParseMethods(pb.Simple_ServiceDesc.ServiceName, c.Limits.Methods)
+		if err != nil {
+			return nil, err
+		}
+		lim, err = limiter.New(limiter.Options{
+			Rate:              limiter.Rate{Limit: c.Limits.Rate, Burst: c.Limits.Burst},
+			Methods:           methods,
+			Key:               c.Limits.Key,
+			MaxStreams:        c.Limits.MaxStreams,
+			MaxStreamMessages: c.Limits.MaxStreamMessages,
+			Exempt:            []string{"/" + health.Health_ServiceDesc.ServiceName + "/"},
+			MeterProvider:     s.mp,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var validator *validate.Validator
+	if c.Validation.Enabled {
+		validator, err = validate.New("simple", c.Validation.Bounds)
+		if err != nil {
+			return nil, err
+		}
+		validator.LeaveToHandler(bulkPutV2Method)
+	}
+
+	if s.ids == nil {
+		a, err := ids.New(c.IDs.Mode, c.IDs.Node, c.IDs.StatePath)
+		if err != nil {
+			return nil, err
+		}
+		s.ids = a
+	}
+	if s.store == nil {
+		st, err := store.New(c.Store.Type, c.Store.Path)
+		if err != nil {
+			return nil, err
+		}
+		s.store = st
+		s.ownStore = true
+	}
+
+	domain := c.Domain
+	if domain == "" {
+		domain = pb.Simple_ServiceDesc.ServiceName
+	}
+	m := newInstruments(s.mp)
+	simple := &newServerImplement{
+		tracer:    s.tp.Tracer(domain),
+		store:     s.store,
+		ids:       s.ids,
+		metrics:   m,
+		sleep:     c.Sleep,
+		validator: validator,
+		payloads:  payloadMode,
+	}
+
+	interceptorOpts := []otelgrpc.Option{
+		otelgrpc.WithTracerProvider(s.tp),
+		otelgrpc.WithMeterProvider(s.mp),
+	}
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		grpc_prometheus.UnaryServerInterceptor,
+		otelgrpc.UnaryServerInterceptor(interceptorOpts...),
+		logging.UnaryServerInterceptor(s.logger),
+		peerUnaryServerInterceptor,
+		logging.PayloadUnaryServerInterceptor(payloadMode),
+		rpcerror.UnaryServerInterceptor(c.DebugErrors),
+		m.unaryServerInterceptor,
+	}
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		grpc_zerolog.NewStreamServerInterceptor(s.logger),
+		grpc_prometheus.StreamServerInterceptor,
+		otelgrpc.StreamServerInterceptor(interceptorOpts...),
+		logging.StreamServerInterceptor(s.logger),
+		peerStreamServerInterceptor,
+		logging.PayloadStreamServerInterceptor(payloadMode),
+		rpcerror.StreamServerInterceptor(c.DebugErrors),
+		m.streamServerInterceptor,
+	}
+	// Limits come before authentication, so unauthenticated floods are limited
+	// too, unless clients are told apart by the principal authentication finds.
+	limitFirst := lim != nil && c.Limits.Key != "principal"
+	if limitFirst {
+		unaryInterceptors = append(unaryInterceptors, lim.UnaryServerInterceptor)
+		streamInterceptors = append(streamInterceptors, lim.StreamServerInterceptor)
+	}
+	if authenticator != nil {
+		unaryInterceptors = append(unaryInterceptors, authenticator.UnaryServerInterceptor)
+		streamInterceptors = append(streamInterceptors, authenticator.StreamServerInterceptor)
+	}
+	if lim != nil && !limitFirst {
+		unaryInterceptors = append(unaryInterceptors, lim.UnaryServerInterceptor)
+		streamInterceptors = append(streamInterceptors, lim.StreamServerInterceptor)
+	}
+	if validator != nil {
+		unaryInterceptors = append(unaryInterceptors, validator.UnaryServerInterceptor)
+		streamInterceptors = append(streamInterceptors, validator.StreamServerInterceptor)
+	}
+	if c.FaultInjection {
+		s.logger.Warn().Msg("fault injection is enabled")
+		faultMeter := s.mp.Meter(instrumentationName + "/fault")
+		unaryInterceptors = append(unaryInterceptors, fault.UnaryServerInterceptor("/simple.Simple/", faultMeter))
+		streamInterceptors = append(streamInterceptors, fault.StreamServerInterceptor("/simple.Simple/", faultMeter))
+	}
+	unaryInterceptors = append(unaryInterceptors, s.unary...)
+	streamInterceptors = append(streamInterceptors, s.stream...)
+
+	serverOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+	}
+	serverOpts = append(serverOpts, messageSizeOptions(c.GRPC)...)
+	// The HTTP front ends share one in-process connection for all their
+	// clients, so it gets a server of its own without the per-connection
+	// limits, which the front ends apply to each of their connections instead.
+	s.inProcessGRPC = grpc.NewServer(append(serverOpts, s.serverOpts...)...)
+	serverOpts = append(serverOpts, transportOptions(c.GRPC)...)
+	if creds != nil {
+		serverOpts = append(serverOpts, grpc.Creds(creds))
+	}
+	s.grpc = grpc.NewServer(append(serverOpts, s.serverOpts...)...)
+
+	pb.RegisterSimpleServer(s, simple)
+
+	s.health = healthcheck.New(pb.Simple_ServiceDesc.ServiceName)
+	health.RegisterHealthServer(s, s.health)
+
+	grpc_prometheus.EnableHandlingTimeHistogram()
+	for _, srv := range []*grpc.Server{s.grpc, s.inProcessGRPC} {
+		grpc_prometheus.Register(srv)
+		reflection.Register(srv)
+	}
+	if c.Debug.Admin {
+		if s.adminCleanup, err = admin.Register(s); err != nil {
+			return nil, err
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	if c.SinglePort && !c.SinglePortAdmin {
+		mux.Handle("/admin/health", s.health.ReadOnly())
+	} else {
+		mux.Handle("/admin/health", s.health)
+	}
+	handleDebug(mux, c)
+	s.http = &http.Server{Handler: mux}
+
+	s.inProcess = inProcessListener{bufconn.Listen(inProcessBufSize)}
+	if s.inProcessConn, err = s.inProcess.dial(); err != nil {
+		return nil, err
+	}
+	gw, err := gateway.New(s.inProcessConn, c.GRPC.MaxRecvMsgSize)
+	if err != nil {
+		return nil, err
+	}
+	s.gateway = &http.Server{Handler: gw}
+
+	var webHandler *web.Handler
+	if c.Web.Enabled {
+		webHandler = web.New(s.inProcessConn, c.GRPC.MaxRecvMsgSize)
+	}
+	switch {
+	case c.SinglePort:
+		h := s.singlePortHandler(mux, webHandler)
+		if webHandler != nil {
+			h = web.CORS(c.Web.CORSOrigins, c.Web.CORSMaxAge, h)
+		}
+		s.mainHTTP = newWebServer(h, c.GRPC)
+	case webHandler != nil:
+		s.mainHTTP = newWebServer(web.CORS(c.Web.CORSOrigins, c.Web.CORSMaxAge, s.webPortHandler(webHandler)), c.GRPC)
+	}
+
+	return s, nil
+}
+
+// GRPCServer returns the server gRPC clients reach on the listener given to
+// Serve. Services registered on it alone are not reachable through the HTTP
+// front ends; RegisterService registers them for both.
+func (s *Server) GRPCServer() *grpc.Server {
+	return s.grpc
+}
+
+// RegisterService registers a service on the gRPC server and on the
+// in-process one the HTTP front ends call. It must be called before Serve.
+func (s *Server) RegisterService(desc *grpc.ServiceDesc, impl interface{}) {
+	s.grpc.RegisterService(desc, impl)
+	s.inProcessGRPC.RegisterService(desc, impl)
+}
+
+// Health returns the health server, to change serving statuses.
+func (s *Server) Health() *healthcheck.Server {
+	return s.health
+}
+
+// Handler serves /metrics, /admin/health and the /debug endpoints turned on.
+func (s *Server) Handler() http.Handler {
+	return s.http.Handler
+}
+
+// Gateway serves the Simple service as HTTP/JSON, calling it in-process.
+// Serve must be running for its calls to get through.
+func (s *Server) Gateway() http.Handler {
+	return s.gateway.Handler
+}
+
+// Serve serves gRPC on lis, along with gRPC-Web and Connect if they are
+// enabled, Handler on the metrics listener and Gateway on the gateway listener
+// if they were given, until ctx is done or serving fails. In single-port mode
+// all of them are served on lis instead. Either way it then shuts down, giving
+// in-flight RPCs the configured drain timeout.
+func (s *Server) Serve(ctx context.Context, lis net.Listener) error {
+	errc := make(chan error, 5)
+	if s.mainHTTP != nil {
+		go func() {
+			if s.webTLS != nil {
+				lis = tls.NewListener(lis, s.webTLS)
+			}
+			err := s.mainHTTP.Serve(lis)
+			if err == http.ErrServerClosed {
+				err = nil
+			}
+			errc <- err
+		}()
+	} else {
+		go func() {
+			errc <- s.grpc.Serve(lis)
+		}()
+	}
+	go s.inProcessGRPC.Serve(s.inProcess)
+	if s.gatewayLis != nil {
+		go func() {
+			s.logger.Info().Msgf("gateway listening on %s", s.gatewayLis.Addr())
+			gatewayLis := s.gatewayLis
+			if s.webTLS != nil {
+				gatewayLis = tls.NewListener(gatewayLis, s.webTLS)
+			}
+			if err := s.gateway.Serve(gatewayLis); err != http.ErrServerClosed {
+				errc <- err
+			}
+		}()
+	}
+	if s.metricsLis != nil {
+		go func() {
+			s.logger.Info().Msgf("prometheus listening on %s", s.metricsLis.Addr())
+			if err := s.http.Serve(s.metricsLis); err != http.ErrServerClosed {
+				errc <- err
+			}
+		}()
+	}
+
+	var err error
+	select {
+	case err = <-errc:
+		if err != nil {
+			s.logger.Error().Msgf("server stopped: %v", err)
+		}
+	case <-ctx.Done():
+		s.logger.Info().Msgf("shutting down, draining for up to %s", s.config.DrainTimeout)
+	}
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), s.config.DrainTimeout)
+	defer cancel()
+	if shutdownErr := s.Shutdown(drainCtx); err == nil {
+		err = shutdownErr
+	}
+	return err
+}
+
+// Shutdown stops serving in order: health goes NOT_SERVING, the HTTP front
+// ends stop accepting and send GOAWAY, in-flight RPCs get until ctx is done to
+// finish before they are cut, then the metrics endpoint stops and a store
+// opened by New is closed. Only the first call does anything.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.shutdownOnce.Do(func() {
+		s.health.Shutdown()
+
+		var frontEndErr error
+		stopped := make(chan struct{})
+		go func() {
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				if s.mainHTTP != nil {
+					s.httpCalls.wait()
+					s.grpc.Stop()
+					return
+				}
+				s.grpc.GracefulStop()
+			}()
+			go func() {
+				defer wg.Done()
+				// The front ends reach gRPC through the in-process server, so
+				// they stop first: requests they still accepted would only fail
+				// once it drains.
+				frontEndErr = s.shutdownFrontEnds(ctx)
+				s.inProcessGRPC.GracefulStop()
+			}()
+			wg.Wait()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			s.logger.Warn().Msg("drain timeout exceeded, stopping remaining RPCs")
+			s.grpc.Stop()
+			s.inProcessGRPC.Stop()
+			<-stopped
+		}
+		s.shutdownErr = frontEndErr
+
+		httpCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.http.Shutdown(httpCtx); err != nil && s.shutdownErr == nil {
+			s.shutdownErr = err
+		}
+		if err := s.release(); err != nil && s.shutdownErr == nil {
+			s.shutdownErr = err
+		}
+	})
+	return s.shutdownErr
+}
+
+// shutdownFrontEnds shuts the gateway server and the HTTP server of the main
+// port down together, closing the connections still busy when ctx is done.
+func (s *Server) shutdownFrontEnds(ctx context.Context) error {
+	var wg sync.WaitGroup
+	servers := []*http.Server{s.gateway, s.mainHTTP}
+	errs := make([]error, len(servers))
+	for i, srv := range servers {
+		if srv == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, srv *http.Server) {
+			defer wg.Done()
+			if err := srv.Shutdown(ctx); err != nil {
+				srv.Close()
+				if ctx.Err() == nil {
+					errs[i] = err
+				}
+			}
+		}(i, srv)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// abandon stops and closes what New built before it failed. Nothing was
+// served yet, so nothing is drained.
+func (s *Server) abandon() {
+	for _, srv := range []*grpc.Server{s.grpc, s.inProcessGRPC} {
+		if srv != nil {
+			srv.Stop()
+		}
+	}
+	if s.inProcess.Listener != nil {
+		s.inProcess.Close()
+	}
+	s.release()
+}
+
+// release closes what New opened: the in-process connection, the admin
+// services and a store opened by New.
+func (s *Server) release() error {
+	if s.inProcessConn != nil {
+		s.inProcessConn.Close()
+	}
+	if s.adminCleanup != nil {
+		s.adminCleanup()
+	}
+	if s.ownStore {
+		return s.store.Close()
+	}
+	return nil
+}
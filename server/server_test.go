@@ -0,0 +1,189 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	health "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/shin5ok/proto-grpc-simple/config"
+	"github.com/shin5ok/proto-grpc-simple/ids"
+	"github.com/shin5ok/proto-grpc-simple/logging"
+	pb "github.com/shin5ok/proto-grpc-simple/pb"
+)
+
+// serve starts a Server built from opts on its own listener and returns a client for it.
+func serve(t *testing.T, ctx context.Context, opts ...Option) (*Server, pb.SimpleClient, <-chan error) {
+	s, conn, done := serveConn(t, ctx, opts...)
+	return s, pb.NewSimpleClient(conn), done
+}
+
+// serveConn is serve returning the connection, for clients of other services.
+func serveConn(t *testing.T, ctx context.Context, opts ...Option) (*Server, *grpc.ClientConn, <-chan error) {
+	s, l, done := serveListener(t, ctx, opts...)
+	return s, dial(t, l), done
+}
+
+// dial connects a gRPC client to l, insecurely unless opts say otherwise,
+// closing it when the test ends.
+func dial(t *testing.T, l interface{ Dial() (net.Conn, error) }, opts ...grpc.DialOption) *grpc.ClientConn {
+	opts = append([]grpc.DialOption{
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return l.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, opts...)
+	conn, err := grpc.Dial("localhost", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// serveListener starts a Server built from opts on a listener of its own, for
+// clients other than gRPC ones. The listener is a loopback TCP one: h2c
+// connections are hijacked from net/http, which resets their read deadline
+// in a way bufconn can time out on.
+func serveListener(t *testing.T, ctx context.Context, opts ...Option) (*Server, *tcpListener, <-chan error) {
+	nl, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := &tcpListener{nl}
+	s, err := New(append([]Option{WithLogger(zerolog.Nop())}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Serve(ctx, l)
+	}()
+	t.Cleanup(func() { s.Shutdown(context.Background()) })
+	return s, l, done
+}
+
+// tcpListener is a listener that can be dialed like a bufconn one.
+type tcpListener struct {
+	net.Listener
+}
+
+func (l *tcpListener) Dial() (net.Conn, error) {
+	return net.Dial("tcp", l.Addr().String())
+}
+
+func TestShutdown(t *testing.T) {
+
+	c := config.Default()
+	c.DrainTimeout = 100 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	s, client, done := serve(t, ctx, WithConfig(c))
+
+	stream, err := client.ListMessage(context.Background(), &pb.Request{Number: 2, Interval: durationpb.New(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < c.DrainTimeout || elapsed > time.Second {
+		t.Errorf("shutdown took %s", elapsed)
+	}
+
+	resp, err := s.Health().Check(context.Background(), &health.HealthCheckRequest{Service: pb.Simple_ServiceDesc.ServiceName})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != health.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("expected NOT_SERVING, got %s", resp.Status)
+	}
+	if _, err := stream.Recv(); err == nil {
+		t.Error("stream was not stopped")
+	}
+}
+
+func TestServerOptions(t *testing.T) {
+
+	var called []string
+	interceptor := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		called = append(called, info.FullMethod)
+		return handler(ctx, req)
+	}
+	a := ids.NewRandom(1)
+	s, client, _ := serve(t, context.Background(), WithUnaryInterceptors(interceptor), WithIDs(a))
+
+	name, err := client.PutMessage(context.Background(), &pb.Message{Message: "foo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name.Id != 0 {
+		t.Errorf("allocator not used, got id %d", name.Id)
+	}
+	if len(called) != 1 || called[0] != "/simple.Simple/PutMessage" {
+		t.Errorf("interceptor saw %v", called)
+	}
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/health", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "simple.Simple") {
+		t.Errorf("admin health returned %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestPayloadLogging(t *testing.T) {
+	ctx := context.Background()
+	for _, mode := range []string{"redact", "off"} {
+		t.Run(mode, func(t *testing.T) {
+			var buf bytes.Buffer
+			c := config.Default()
+			c.Log.Payload = mode
+			l, err := logging.New(logging.Config{}, zerolog.SyncWriter(&buf))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, client, _ := serve(t, ctx, WithConfig(c), WithLogger(l), WithTracerProvider(sdktrace.NewTracerProvider()))
+
+			name, err := client.PutMessage(ctx, &pb.Message{Message: "put secret"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			client.GetMessage(ctx, &pb.Name{Id: name.Id, Text: "get secret"})
+			bulk, err := client.BulkPutMessage(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bulk.Send(&pb.Message{Message: "bulk secret"})
+			if _, err := bulk.CloseAndRecv(); err != nil {
+				t.Fatal(err)
+			}
+
+			logs := buf.String()
+			if strings.Contains(logs, "secret") || strings.Contains(logs, name.Text) {
+				t.Errorf("payloads were logged: %s", logs)
+			}
+			if redacted := strings.Contains(logs, logging.Redacted); redacted != (mode == "redact") {
+				t.Errorf("%s: redacted payloads logged: %v", mode, redacted)
+			}
+			for _, line := range strings.Split(logs, "\n") {
+				if strings.Contains(line, "grpc.request.payload") && !strings.Contains(line, "logging.googleapis.com/trace") {
+					t.Errorf("payload logged without its trace: %s", line)
+				}
+			}
+		})
+	}
+}
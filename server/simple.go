@@ -0,0 +1,272 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"encoding/json"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/shin5ok/proto-grpc-simple/ids"
+	"github.com/shin5ok/proto-grpc-simple/logging"
+	pb "github.com/shin5ok/proto-grpc-simple/pb"
+	"github.com/shin5ok/proto-grpc-simple/rpcerror"
+	"github.com/shin5ok/proto-grpc-simple/store"
+	"github.com/shin5ok/proto-grpc-simple/validate"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// bulkPutV2Method reports invalid messages in its summary rather than failing.
+const bulkPutV2Method = "/simple.Simple/BulkPutMessageV2"
+
+// maxListNumber bounds the number of messages ListMessage sends, whether or
+// not requests are validated, as the validation rules do by default.
+const maxListNumber = 10000
+
+// maxLoggedBulkPut bounds the BulkPutMessage messages logged together once
+// the stream ends.
+const maxLoggedBulkPut = 100
+
+type newServerImplement struct {
+	tracer  trace.Tracer
+	store   store.Store
+	ids     ids.Allocator
+	metrics *instruments
+	// sleep is the ListMessage interval used when the request has none.
+	sleep time.Duration
+	// validator checks each BulkPutMessageV2 message, when validation is on.
+	validator *validate.Validator
+	// payloads says whether the handlers log payloads, as the interceptors do.
+	payloads logging.PayloadMode
+}
+
+func (n *newServerImplement) GetMessage(ctx context.Context, name *pb.Name) (*pb.Message, error) {
+	ctx, span := n.tracer.Start(ctx, "get message")
+	defer span.End()
+
+	n.payloads.Payload(logging.Ctx(ctx).Info(), "Name as args", name).Send()
+
+	message, err := n.store.Get(ctx, name)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, rpcerror.New(codes.NotFound, rpcerror.ReasonMessageNotFound, fmt.Sprintf("message %+v is not found", name),
+			rpcerror.WithMetadata("id", fmt.Sprint(name.GetId()), "text", name.GetText()))
+	}
+	if err != nil {
+		return nil, rpcerror.New(codes.Internal, rpcerror.ReasonStoreFailed, "reading the message failed", rpcerror.WithCause(err))
+	}
+	return message, nil
+}
+
+func (n *newServerImplement) PutMessage(ctx context.Context, message *pb.Message) (*pb.Name, error) {
+	ctx, span := n.tracer.Start(ctx, "put message")
+	defer span.End()
+
+	n.payloads.Payload(logging.Ctx(ctx).Info(), "Params", message).Send()
+
+	return n.put(ctx, message)
+}
+
+func (n *newServerImplement) put(ctx context.Context, message *pb.Message) (*pb.Name, error) {
+	id, err := n.ids.Next()
+	if err != nil {
+		return nil, rpcerror.New(codes.ResourceExhausted, rpcerror.ReasonIDsExhausted, "no message ids left", rpcerror.WithCause(err))
+	}
+	nameText := uuid.New().String()
+	name := &pb.Name{Text: nameText, Id: id}
+	if err := n.store.Put(ctx, name, message); err != nil {
+		return nil, rpcerror.New(codes.Internal, rpcerror.ReasonStoreFailed, "storing the message failed", rpcerror.WithCause(err))
+	}
+	n.metrics.messagesStored.Add(ctx, 1)
+	return name, nil
+}
+
+func (n *newServerImplement) PingPong(ctx context.Context, message *pb.Message) (*pb.Message, error) {
+	ctx, span := n.tracer.Start(ctx, "ping pong")
+	defer span.End()
+
+	return &pb.Message{Message: "Pong"}, nil
+}
+
+func (n *newServerImplement) ListMessage(req *pb.Request, stream pb.Simple_ListMessageServer) error {
+
+	ctx, span := n.tracer.Start(stream.Context(), "list message")
+	defer span.End()
+
+	n.payloads.Payload(logging.Ctx(ctx).Info(), "Params", req).Send()
+
+	max := int(req.Number)
+	if max > maxListNumber {
+		return invalidField("number", fmt.Sprintf("number must be at most %d: %d", maxListNumber, max))
+	}
+	p, err := pacingFromRequest(req, n.sleep)
+	if err != nil {
+		return err
+	}
+
+	delivered := 0
+	err = func() error {
+		ctx, span := n.tracer.Start(ctx, "doing list message")
+		defer span.End()
+
+		if err := wait(ctx, p.initialDelay); err != nil {
+			return err
+		}
+		for i := 0; i < max; i++ {
+			if i > 0 {
+				if err := wait(ctx, p.next()); err != nil {
+					return err
+				}
+			}
+			result := &pb.Message{Message: fmt.Sprintf("send %d", i), Payload: p.payload()}
+			if err := stream.Send(result); err != nil {
+				if ctx.Err() != nil {
+					return status.FromContextError(ctx.Err()).Err()
+				}
+				if _, ok := status.FromError(err); ok {
+					return err
+				}
+				return rpcerror.New(codes.Internal, rpcerror.ReasonSendFailed, fmt.Sprintf("sending message %d failed", i), rpcerror.WithCause(err))
+			}
+			delivered++
+			n.metrics.listMessageSent.Add(ctx, 1)
+		}
+		return nil
+	}()
+
+	span.SetAttributes(attribute.Int("list_message.delivered", delivered))
+	n.metrics.listMessageDelivered.Record(detach(ctx), int64(delivered), metric.WithAttributes(codeAttr(err)))
+	if err != nil {
+		span.SetStatus(otelcodes.Error, err.Error())
+	}
+	return err
+}
+
+// wait pauses for d, returning early with Canceled or DeadlineExceeded when ctx is done.
+func wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		if err := ctx.Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+func (n *newServerImplement) BulkPutMessage(stream pb.Simple_BulkPutMessageServer) error {
+	ctx := stream.Context()
+	logger := logging.Ctx(ctx)
+
+	// Messages are only kept to be logged, and no more than
+	// maxLoggedBulkPut of them, so long streams take no more memory.
+	var results []proto.Message
+	var i = 0
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return recvError(ctx, err)
+		}
+		n.payloads.Payload(logger.Info().Int("i", i), "data", req).Send()
+		if n.payloads != logging.PayloadOff && len(results) < maxLoggedBulkPut {
+			results = append(results, req)
+		}
+		i++
+	}
+	n.metrics.bulkPutBatchSize.Record(ctx, int64(i), metric.WithAttributes(attribute.String("rpc.method", "BulkPutMessage")))
+
+	if n.payloads != logging.PayloadOff {
+		logged := n.payloads.Payloads(results...)
+		logger.Info().
+			Str("Results", fmt.Sprintf("message %+v", logged)).
+			Send()
+		data, err := json.Marshal(logged)
+		if err != nil {
+			return rpcerror.New(codes.Internal, rpcerror.ReasonEncodingFailed, "encoding the messages failed", rpcerror.WithCause(err))
+		}
+		logger.Info().Int("count", i).RawJSON("result", data).Send()
+	}
+	return stream.SendAndClose(&emptypb.Empty{})
+}
+
+func (n *newServerImplement) BulkPutMessageV2(stream pb.Simple_BulkPutMessageV2Server) error {
+	ctx, span := n.tracer.Start(stream.Context(), "bulk put message")
+	defer span.End()
+
+	summary := &pb.BulkPutSummary{}
+	for i := 0; ; i++ {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return recvError(ctx, err)
+		}
+		name, err := n.putValid(ctx, req)
+		if err != nil {
+			st := status.Convert(err)
+			summary.Rejected++
+			summary.Errors = append(summary.Errors, &pb.BulkPutError{
+				Index:   int32(i),
+				Code:    int32(st.Code()),
+				Message: st.Message(),
+			})
+			continue
+		}
+		summary.Accepted++
+		summary.Names = append(summary.Names, name)
+	}
+
+	n.metrics.bulkPutBatchSize.Record(ctx, int64(summary.Accepted+summary.Rejected), metric.WithAttributes(attribute.String("rpc.method", "BulkPutMessageV2")))
+
+	logging.Ctx(ctx).
+		Info().
+		Int32("accepted", summary.Accepted).
+		Int32("rejected", summary.Rejected).
+		Send()
+
+	return stream.SendAndClose(summary)
+}
+
+// putValid is put for a BulkPutMessageV2 message, which is rejected on its
+// own if it is invalid.
+func (n *newServerImplement) putValid(ctx context.Context, message *pb.Message) (*pb.Name, error) {
+	if n.validator != nil {
+		if violations := n.validator.Violations(message, n.validator.Required(bulkPutV2Method)); len(violations) > 0 {
+			return nil, validate.Error("invalid message", violations)
+		}
+	}
+	return n.put(ctx, message)
+}
+
+// recvError turns an error from stream.Recv into a gRPC status,
+// reporting client cancellation and deadlines as such.
+func recvError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return status.FromContextError(ctxErr).Err()
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return rpcerror.New(codes.Unknown, rpcerror.ReasonReceiveFailed, "receiving a message failed", rpcerror.WithCause(err))
+}
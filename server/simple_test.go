@@ -0,0 +1,449 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/shin5ok/proto-grpc-simple/config"
+	pb "github.com/shin5ok/proto-grpc-simple/pb"
+	"github.com/shin5ok/proto-grpc-simple/store"
+)
+
+var lis *bufconn.Listener
+var metricReader = sdkmetric.NewManualReader()
+
+const bufSize = 1024 * 1024
+
+// https://github.com/castaneai/grpc-testing-with-bufconn/blob/master/server/server_test.go
+func init() {
+	lis = bufconn.Listen(bufSize)
+	s, err := New(
+		WithLogger(zerolog.Nop()),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(metricReader))),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	go func() {
+		if err := s.Serve(context.Background(), lis); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+}
+
+func bufDialer(ctx context.Context, address string) (net.Conn, error) {
+	return lis.Dial()
+}
+
+func TestGetMessage(t *testing.T) {
+
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "localhost", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	client := pb.NewSimpleClient(conn)
+
+	for _, param := range []*pb.Message{
+		{Name: &pb.Name{Id: 10, Text: "foo"}, Message: "foo"},
+		{Name: &pb.Name{Id: 20, Text: "テスト"}, Message: "テスト"},
+		{Name: &pb.Name{Id: 100000, Text: "big int number"}, Message: "big int number"},
+	} {
+
+		t.Run(param.Message, func(t *testing.T) {
+			name, err := client.PutMessage(ctx, param)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, key := range []*pb.Name{name, {Id: name.Id}} {
+				resp, err := client.GetMessage(ctx, key)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if resp.Message != param.Message {
+					t.Error(resp.Message)
+				}
+				if resp.Name.Text != name.Text || resp.Name.Id != name.Id {
+					t.Errorf("%+v is not %+v", resp.Name, name)
+				}
+			}
+		})
+
+	}
+
+	_, err = client.GetMessage(ctx, &pb.Name{Text: "not stored"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+}
+
+func TestListMessage(t *testing.T) {
+
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "localhost", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	client := pb.NewSimpleClient(conn)
+
+	stream, err := client.ListMessage(ctx, &pb.Request{Number: 10})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := 0
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Error(err)
+		}
+
+		shouldValue := fmt.Sprintf("send %d", n)
+		if response.Message != shouldValue {
+			t.Error(response.Message)
+		}
+		n++
+	}
+}
+
+func TestListMessagePacing(t *testing.T) {
+
+	ctx := context.Background()
+	client := pb.NewSimpleClient(dial(t, lis))
+
+	start := time.Now()
+	stream, err := client.ListMessage(ctx, &pb.Request{
+		Number:       3,
+		Interval:     durationpb.New(20 * time.Millisecond),
+		Jitter:       durationpb.New(5 * time.Millisecond),
+		InitialDelay: durationpb.New(30 * time.Millisecond),
+		PayloadSize:  16,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := 0
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(response.Payload) != 16 {
+			t.Errorf("unexpected payload size %d", len(response.Payload))
+		}
+		n++
+	}
+	if n != 3 {
+		t.Errorf("expected 3 messages, got %d", n)
+	}
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Errorf("stream finished too early: %s", elapsed)
+	}
+
+	stream, err = client.ListMessage(ctx, &pb.Request{Number: 1, Interval: durationpb.New(-time.Second)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+
+	// Checked even when validation is off.
+	c := config.Default()
+	c.Validation.Enabled = false
+	_, unvalidated, _ := serve(t, ctx, WithConfig(c))
+	stream, err = unvalidated.ListMessage(ctx, &pb.Request{Number: maxListNumber + 1, Interval: durationpb.New(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("too many messages: expected InvalidArgument, got %v", err)
+	}
+	if _, err := pacingFromRequest(&pb.Request{PayloadSize: maxPayloadSize + 1}, 0); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("too large payload: expected InvalidArgument, got %v", err)
+	}
+}
+
+func deliveredCount(code codes.Code) uint64 {
+	var rm metricdata.ResourceMetrics
+	metricReader.Collect(context.Background(), &rm)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			h, ok := m.Data.(metricdata.Histogram[int64])
+			if m.Name != "simple.list_message.delivered" || !ok {
+				continue
+			}
+			for _, dp := range h.DataPoints {
+				if v, _ := dp.Attributes.Value("rpc.grpc.status_code"); v.AsInt64() == int64(code) {
+					return dp.Count
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func counterValue(name string) int64 {
+	var rm metricdata.ResourceMetrics
+	metricReader.Collect(context.Background(), &rm)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok && m.Name == name {
+				var v int64
+				for _, dp := range sum.DataPoints {
+					v += dp.Value
+				}
+				return v
+			}
+		}
+	}
+	return 0
+}
+
+// slowConn holds back what the client writes once slow is set, so a cancel
+// sent when the client's deadline passes reaches the server after its own.
+type slowConn struct {
+	net.Conn
+	slow *int32
+}
+
+func (c slowConn) Write(b []byte) (int, error) {
+	if atomic.LoadInt32(c.slow) == 1 {
+		time.Sleep(200 * time.Millisecond)
+	}
+	return c.Conn.Write(b)
+}
+
+func TestListMessageCancel(t *testing.T) {
+
+	var slow int32
+	dialer := func(ctx context.Context, address string) (net.Conn, error) {
+		c, err := bufDialer(ctx, address)
+		return slowConn{c, &slow}, err
+	}
+	conn, err := grpc.DialContext(context.Background(), "localhost", grpc.WithContextDialer(dialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	client := pb.NewSimpleClient(conn)
+
+	for _, code := range []codes.Code{codes.Canceled, codes.DeadlineExceeded} {
+		t.Run(code.String(), func(t *testing.T) {
+			before := deliveredCount(code)
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+			if code == codes.DeadlineExceeded {
+				ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+			}
+			defer cancel()
+
+			stream, err := client.ListMessage(ctx, &pb.Request{Number: 10, Interval: durationpb.New(time.Hour)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := stream.Recv(); err != nil {
+				t.Fatal(err)
+			}
+			if code == codes.Canceled {
+				cancel()
+			} else {
+				atomic.StoreInt32(&slow, 1)
+			}
+			if _, err := stream.Recv(); status.Code(err) != code {
+				t.Errorf("expected %s, got %v", code, err)
+			}
+
+			for start := time.Now(); deliveredCount(code) == before; {
+				if time.Since(start) > time.Second {
+					t.Fatal("server did not stop the stream")
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		})
+	}
+}
+
+func TestPutMessage(t *testing.T) {
+
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx, "localhost", grpc.WithContextDialer(bufDialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	client := pb.NewSimpleClient(conn)
+
+	name := &pb.Name{Id: 10, Text: "foo"}
+	message := &pb.Message{Message: "foo is message", Name: name}
+
+	resp, err := client.PutMessage(ctx, message)
+	if err != nil {
+		t.Error(err)
+	}
+
+	regexStr := `^[a-fA-F0-9]{8}-[a-fA-F0-9]{4}-4[a-fA-F0-9]{3}-[8|9|aA|bB][a-fA-F0-9]{3}-[a-fA-F0-9]{12}$`
+	re := regexp.MustCompile(regexStr)
+
+	if !re.MatchString(resp.Text) {
+		t.Errorf("not match message: %s", resp.Text)
+
+	}
+
+	stored := counterValue("simple.messages.stored")
+	next, err := client.PutMessage(ctx, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Id <= resp.Id {
+		t.Errorf("id %d is not greater than %d", next.Id, resp.Id)
+	}
+	if v := counterValue("simple.messages.stored"); v != stored+1 {
+		t.Errorf("simple.messages.stored is %d, expected %d", v, stored+1)
+	}
+
+}
+
+func TestBulkPutMessageV2(t *testing.T) {
+
+	ctx := context.Background()
+	client := pb.NewSimpleClient(dial(t, lis))
+
+	stream, err := client.BulkPutMessageV2(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := []string{"foo", "bar", "baz"}
+	for _, m := range messages {
+		if err := stream.Send(&pb.Message{Message: m}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	summary, err := stream.CloseAndRecv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.Accepted != 3 || summary.Rejected != 0 || len(summary.Names) != 3 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+	for i, name := range summary.Names {
+		resp, err := client.GetMessage(ctx, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Message != messages[i] {
+			t.Error(resp.Message)
+		}
+	}
+}
+
+type failingStore struct {
+	store.Store
+}
+
+func (f failingStore) Put(ctx context.Context, name *pb.Name, message *pb.Message) error {
+	if message.Message == "fail" {
+		return fmt.Errorf("failed to store")
+	}
+	return f.Store.Put(ctx, name, message)
+}
+
+func TestBulkPutMessageV2Rejected(t *testing.T) {
+
+	ctx := context.Background()
+	_, client, _ := serve(t, ctx, WithStore(failingStore{store.NewMemory()}))
+
+	stream, err := client.BulkPutMessageV2(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range []string{"foo", "fail", "bar"} {
+		if err := stream.Send(&pb.Message{Message: m}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	summary, err := stream.CloseAndRecv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.Accepted != 2 || summary.Rejected != 1 || len(summary.Errors) != 1 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+	if e := summary.Errors[0]; e.Index != 1 || codes.Code(e.Code) != codes.Internal {
+		t.Errorf("unexpected error %+v", e)
+	}
+}
+
+func TestBulkPutMessageLogsBounded(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	_, client, _ := serve(t, ctx, WithLogger(zerolog.New(zerolog.SyncWriter(&buf))))
+
+	bulk, err := client.BulkPutMessage(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent := maxLoggedBulkPut + 5
+	for i := 0; i < sent; i++ {
+		if err := bulk.Send(&pb.Message{Message: fmt.Sprint(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := bulk.CloseAndRecv(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range strings.Split(buf.String(), "\n") {
+		var entry struct {
+			Count  int               `json:"count"`
+			Result []json.RawMessage `json:"result"`
+		}
+		if json.Unmarshal([]byte(line), &entry) != nil || entry.Result == nil {
+			continue
+		}
+		if entry.Count != sent || len(entry.Result) != maxLoggedBulkPut {
+			t.Errorf("logged %d of %d messages, count %d", len(entry.Result), sent, entry.Count)
+		}
+		return
+	}
+	t.Errorf("results not logged: %s", buf.String())
+}
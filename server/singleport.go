@@ -0,0 +1,72 @@
+package server
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/shin5ok/proto-grpc-simple/web"
+)
+
+// singlePortHandler routes each request on the single port: gRPC to the gRPC
+// server's own HTTP/2 handler, so compression, call options and the client's
+// address and TLS identity are as on a gRPC port, gRPC-Web and Connect to
+// webHandler when it is set, the paths of mux to mux and anything else to the
+// gateway. Requests are routed one by one because proxies such as Cloud
+// Run's send all of them over the same HTTP/2 connections.
+func (s *Server) singlePortHandler(mux *http.ServeMux, webHandler *web.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case web.IsGRPC(r):
+			s.serveGRPC(w, r)
+		case webHandler != nil && webHandler.Handles(r):
+			webHandler.ServeHTTP(w, r)
+		default:
+			if _, pattern := mux.Handler(r); pattern != "" {
+				mux.ServeHTTP(w, r)
+				return
+			}
+			s.gateway.Handler.ServeHTTP(w, r)
+		}
+	})
+}
+
+// serveGRPC hands the gRPC call r to the gRPC server, counting it so
+// Shutdown can drain it.
+func (s *Server) serveGRPC(w http.ResponseWriter, r *http.Request) {
+	if !s.httpCalls.add() {
+		http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	defer s.httpCalls.done()
+	s.grpc.ServeHTTP(w, r)
+}
+
+// inFlight counts the gRPC calls the HTTP server of the main port hands to
+// the gRPC server: GracefulStop cannot drain the transports of
+// grpc.Server.ServeHTTP.
+type inFlight struct {
+	mu      sync.Mutex
+	closing bool
+	wg      sync.WaitGroup
+}
+
+// add counts a new call, unless wait was called.
+func (f *inFlight) add() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closing {
+		return false
+	}
+	f.wg.Add(1)
+	return true
+}
+
+func (f *inFlight) done() { f.wg.Done() }
+
+// wait turns new calls away and returns once the counted ones are done.
+func (f *inFlight) wait() {
+	f.mu.Lock()
+	f.closing = true
+	f.mu.Unlock()
+	f.wg.Wait()
+}
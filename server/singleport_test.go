@@ -0,0 +1,188 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"golang.org/x/net/http2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/encoding/gzip"
+	health "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/shin5ok/proto-grpc-simple/certgen"
+	"github.com/shin5ok/proto-grpc-simple/config"
+	pb "github.com/shin5ok/proto-grpc-simple/pb"
+	"github.com/shin5ok/proto-grpc-simple/rpcerror"
+)
+
+func TestSinglePort(t *testing.T) {
+	c := config.Default()
+	c.SinglePort = true
+	_, l, _ := serveListener(t, context.Background(), WithConfig(c))
+	conn := dial(t, l)
+	client := pb.NewSimpleClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	name, err := client.PutMessage(ctx, &pb.Message{Message: "single"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m, err := client.GetMessage(ctx, name); err != nil || m.Message != "single" {
+		t.Errorf("get: %v %v", m, err)
+	}
+	// Served by the gRPC server itself, calls keep their compression.
+	if m, err := client.GetMessage(ctx, name, grpc.UseCompressor(gzip.Name)); err != nil || m.Message != "single" {
+		t.Errorf("get compressed: %v %v", m, err)
+	}
+	_, err = client.GetMessage(ctx, &pb.Name{Text: "missing"})
+	if d := rpcerror.Decode(err); d.Code != codes.NotFound || d.Reason() != rpcerror.ReasonMessageNotFound {
+		t.Errorf("get missing: %v", d)
+	}
+	stream, err := client.ListMessage(ctx, &pb.Request{Number: 2, Interval: durationpb.New(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for ; err == nil; n++ {
+		_, err = stream.Recv()
+	}
+	if err != io.EOF || n != 3 {
+		t.Errorf("list: %d messages, %v", n-1, err)
+	}
+	if resp, err := health.NewHealthClient(conn).Check(ctx, &health.HealthCheckRequest{}); err != nil || resp.Status != health.HealthCheckResponse_SERVING {
+		t.Errorf("health: %v %v", resp, err)
+	}
+
+	// gRPC and plain HTTP requests on one HTTP/2 connection, as proxies send them.
+	dials := 0
+	h2c := &http.Client{Transport: &http2.Transport{AllowHTTP: true, DialTLS: func(string, string, *tls.Config) (net.Conn, error) {
+		dials++
+		return l.Dial()
+	}}}
+	req, _ := proto.Marshal(&pb.Message{Message: "over http"})
+	resp, err := h2c.Post("http://localhost/simple.Simple/PutMessage", "application/grpc", bytes.NewReader(append([]byte{0, 0, 0, 0, byte(len(req))}, req...)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.Trailer.Get("Grpc-Status") != "0" || len(body) < 5 || proto.Unmarshal(body[5:], &pb.Name{}) != nil {
+		t.Errorf("grpc over http: %v %q", resp.Trailer, body)
+	}
+	for path, want := range map[string]string{
+		"/metrics":                              "grpc_server_handled_total",
+		"/admin/health":                         "SERVING",
+		fmt.Sprintf("/v1/messages/%d", name.Id): "single",
+	} {
+		resp, err := h2c.Get("http://localhost" + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), want) {
+			t.Errorf("%s: %d %.100s", path, resp.StatusCode, body)
+		}
+	}
+	if dials != 1 {
+		t.Errorf("dialed %d times", dials)
+	}
+
+	// Anyone reaching the port could take the service out of rotation.
+	resp, err = h2c.Post("http://localhost/admin/health?status=NOT_SERVING", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("changing health: %d", resp.StatusCode)
+	}
+}
+
+func TestSinglePortTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, _ := certgen.NewCA(certgen.Options{CommonName: "ca"})
+	serverCert, _ := ca.Issue(certgen.Options{CommonName: "localhost", Hosts: []string{"localhost"}})
+	clientCert, _ := ca.Issue(certgen.Options{CommonName: "client", Client: true})
+	c := config.Default()
+	c.SinglePort = true
+	c.TLS.CertFile, c.TLS.KeyFile = filepath.Join(dir, "server.pem"), filepath.Join(dir, "server-key.pem")
+	c.TLS.ClientCAFile = filepath.Join(dir, "ca.pem")
+	if err := serverCert.Write(c.TLS.CertFile, c.TLS.KeyFile); err != nil {
+		t.Fatal(err)
+	}
+	if err := ca.Write(c.TLS.ClientCAFile, filepath.Join(dir, "ca-key.pem")); err != nil {
+		t.Fatal(err)
+	}
+	var logs bytes.Buffer
+	_, l, _ := serveListener(t, context.Background(), WithConfig(c), WithLogger(zerolog.New(zerolog.SyncWriter(&logs))))
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Cert)
+	creds := credentials.NewTLS(&tls.Config{RootCAs: roots, Certificates: []tls.Certificate{clientCert.TLSCertificate()}})
+	conn := dial(t, l, grpc.WithTransportCredentials(creds))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := pb.NewSimpleClient(conn).PutMessage(ctx, &pb.Message{Message: "tls"}, grpc.UseCompressor(gzip.Name)); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(logs.String(), `"tls.client.subject":"CN=client"`) {
+		t.Errorf("client certificate not seen: %s", logs.String())
+	}
+}
+
+func TestSinglePortShutdown(t *testing.T) {
+	c := config.Default()
+	c.SinglePort = true
+	c.DrainTimeout = 500 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	_, l, done := serveListener(t, ctx, WithConfig(c))
+	stream, err := pb.NewSimpleClient(dial(t, l)).ListMessage(context.Background(), &pb.Request{Number: 2, Interval: durationpb.New(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+
+	// New connections are refused while the stream drains, rather than
+	// accepted only to fail on the stopping gRPC server.
+	start := time.Now()
+	cancel()
+	for {
+		nc, err := l.Dial()
+		if err != nil {
+			break
+		}
+		nc.Close()
+		if time.Since(start) > c.DrainTimeout/2 {
+			t.Fatal("still accepting connections while draining")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("shutdown took %s", elapsed)
+	}
+	if _, err := stream.Recv(); err == nil {
+		t.Error("stream was not stopped")
+	}
+}
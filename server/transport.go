@@ -0,0 +1,64 @@
+package server
+
+import (
+	"net/http"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+
+	"github.com/shin5ok/proto-grpc-simple/config"
+)
+
+// transportOptions turns the per-connection settings of c into gRPC server
+// options. Zero values keep the gRPC defaults.
+func transportOptions(c config.GRPC) []grpc.ServerOption {
+	opts := []grpc.ServerOption{
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionIdle:     c.MaxConnectionIdle,
+			MaxConnectionAge:      c.MaxConnectionAge,
+			MaxConnectionAgeGrace: c.MaxConnectionAgeGrace,
+			Time:                  c.KeepaliveTime,
+			Timeout:               c.KeepaliveTimeout,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             c.KeepaliveMinTime,
+			PermitWithoutStream: c.KeepalivePermitWithoutStream,
+		}),
+	}
+	if c.MaxConcurrentStreams > 0 {
+		opts = append(opts, grpc.MaxConcurrentStreams(uint32(c.MaxConcurrentStreams)))
+	}
+	return opts
+}
+
+// messageSizeOptions turns the message sizes of c into gRPC server options.
+// Zero values keep the gRPC defaults.
+func messageSizeOptions(c config.GRPC) []grpc.ServerOption {
+	var opts []grpc.ServerOption
+	if c.MaxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(c.MaxRecvMsgSize))
+	}
+	if c.MaxSendMsgSize > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(c.MaxSendMsgSize))
+	}
+	return opts
+}
+
+// newWebServer serves h over HTTP/1.1 and HTTP/2, h2c included, with the
+// settings of c that HTTP connections have too. Shutdown sends GOAWAY on its
+// HTTP/2 connections, which h2c would otherwise leave open.
+func newWebServer(h http.Handler, c config.GRPC) *http.Server {
+	h2s := &http2.Server{
+		MaxConcurrentStreams: uint32(c.MaxConcurrentStreams),
+		IdleTimeout:          c.MaxConnectionIdle,
+	}
+	srv := &http.Server{
+		Handler:     h2c.NewHandler(h, h2s),
+		IdleTimeout: c.MaxConnectionIdle,
+	}
+	// Only fails on a TLS config with no HTTP/2 cipher suite, and srv has none.
+	_ = http2.ConfigureServer(srv, h2s)
+	return srv
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/shin5ok/proto-grpc-simple/config"
+	pb "github.com/shin5ok/proto-grpc-simple/pb"
+)
+
+func TestTransportOptions(t *testing.T) {
+	c := config.Default()
+	c.GRPC.MaxRecvMsgSize = 4096
+	c.GRPC.MaxSendMsgSize = 1024
+	c.GRPC.MaxConnectionAge = 50 * time.Millisecond
+	c.GRPC.MaxConnectionAgeGrace = 50 * time.Millisecond
+	s, client, _ := serve(t, context.Background(), WithConfig(c))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := client.PutMessage(ctx, &pb.Message{Message: "big", Payload: make([]byte, 5000)}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("over max_recv_msg_size: %v", err)
+	}
+	name, err := client.PutMessage(ctx, &pb.Message{Message: "big", Payload: make([]byte, 2000)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetMessage(ctx, name); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("over max_send_msg_size: %v", err)
+	}
+
+	// The gateway caps bodies the same way, whether over or under its default.
+	body := func(n int) io.Reader {
+		return strings.NewReader(`{"message":"spaced"` + strings.Repeat(" ", n) + "}")
+	}
+	w := httptest.NewRecorder()
+	s.Gateway().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/messages", body(5000)))
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("gateway over max_recv_msg_size: %d %s", w.Code, w.Body)
+	}
+	large := config.Default()
+	large.GRPC.MaxRecvMsgSize = 8 << 20
+	s, _, _ = serve(t, context.Background(), WithConfig(large))
+	w = httptest.NewRecorder()
+	s.Gateway().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/messages", body(5<<20)))
+	if w.Code != http.StatusOK {
+		t.Errorf("gateway under max_recv_msg_size: %d %s", w.Code, w.Body)
+	}
+
+	// Streams outliving the connection age and its grace are cut.
+	stream, err := client.ListMessage(ctx, &pb.Request{Number: 2, Interval: durationpb.New(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("after max_connection_age: %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/shin5ok/proto-grpc-simple/config"
+	pb "github.com/shin5ok/proto-grpc-simple/pb"
+	"github.com/shin5ok/proto-grpc-simple/rpcerror"
+)
+
+func TestValidation(t *testing.T) {
+
+	c := config.Default()
+	c.Validation.Enabled = true
+	c.Validation.Bounds = []string{"Request.number=5"}
+	_, client, _ := serve(t, context.Background(), WithConfig(c))
+	ctx := context.Background()
+
+	stream, err := client.ListMessage(ctx, &pb.Request{Number: 6})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if d := rpcerror.Decode(err); d.Code != codes.InvalidArgument || d.Reason() != rpcerror.ReasonInvalidRequest || len(d.BadRequest.GetFieldViolations()) != 1 {
+		t.Errorf("number over the bound: got %v", d)
+	}
+
+	bulk, err := client.BulkPutMessageV2(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bulk.Send(&pb.Message{Message: "foo"})
+	bulk.Send(&pb.Message{})
+	bulk.Send(&pb.Message{Message: "bar"})
+	summary, err := bulk.CloseAndRecv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Accepted != 2 || summary.Rejected != 1 || len(summary.Names) != 2 || len(summary.Errors) != 1 {
+		t.Fatalf("empty message in a stream: got %+v", summary)
+	}
+	if e := summary.Errors[0]; e.Index != 1 || codes.Code(e.Code) != codes.InvalidArgument || !strings.Contains(e.Message, "message is required") {
+		t.Errorf("unexpected error %+v", e)
+	}
+
+	// BulkPutMessage takes empty messages as it always has.
+	v1, err := client.BulkPutMessage(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v1.Send(&pb.Message{})
+	if _, err := v1.CloseAndRecv(); err != nil {
+		t.Errorf("empty message to BulkPutMessage: %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"crypto/tls"
+	"net/http"
+
+	"github.com/shin5ok/proto-grpc-simple/web"
+)
+
+// webPortHandler routes each request on the main port when gRPC-Web and
+// Connect are served there: gRPC to the gRPC server's own HTTP/2 handler, as
+// on the single port, and anything else to webHandler.
+func (s *Server) webPortHandler(webHandler *web.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if web.IsGRPC(r) {
+			s.serveGRPC(w, r)
+			return
+		}
+		webHandler.ServeHTTP(w, r)
+	})
+}
+
+// webTLSConfig is c also offering HTTP/1.1, for browsers and curl.
+func webTLSConfig(c *tls.Config) *tls.Config {
+	protos := []string{"h2", "http/1.1"}
+	c = c.Clone()
+	c.NextProtos = protos
+	if get := c.GetConfigForClient; get != nil {
+		c.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			cc, err := get(hello)
+			if cc == nil || err != nil {
+				return cc, err
+			}
+			cc = cc.Clone()
+			cc.NextProtos = protos
+			return cc, nil
+		}
+	}
+	return c
+}
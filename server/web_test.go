@@ -0,0 +1,148 @@
+package server
+
+import (
+	"bytes"
+	gz "compress/gzip"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/http2"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/shin5ok/proto-grpc-simple/config"
+	pb "github.com/shin5ok/proto-grpc-simple/pb"
+	"github.com/shin5ok/proto-grpc-simple/rpcerror"
+)
+
+func TestWeb(t *testing.T) {
+	c := config.Default()
+	c.Web.Enabled = true
+	c.Web.CORSOrigins = []string{"https://app.example"}
+	_, l, _ := serveListener(t, context.Background(), WithConfig(c))
+
+	// Native gRPC shares the port.
+	conn := dial(t, l)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	name, err := pb.NewSimpleClient(conn).PutMessage(ctx, &pb.Message{Message: "native"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	http1 := &http.Client{Transport: &http.Transport{DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) { return l.Dial() }}}
+	h2c := &http.Client{Transport: &http2.Transport{AllowHTTP: true, DialTLS: func(string, string, *tls.Config) (net.Conn, error) { return l.Dial() }}}
+	post := func(client *http.Client, method, contentType string, body []byte, header ...string) (*http.Response, []byte) {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodPost, "http://localhost/simple.Simple/"+method, bytes.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		return resp, b
+	}
+	envelope := func(flags byte, b []byte) []byte {
+		return append([]byte{flags, byte(len(b) >> 24), byte(len(b) >> 16), byte(len(b) >> 8), byte(len(b))}, b...)
+	}
+	// frames splits a streamed body into its messages and the flags of the last one.
+	frames := func(b []byte) ([]string, byte) {
+		var out []string
+		var flags byte
+		for len(b) >= 5 {
+			n := int(b[1])<<24 | int(b[2])<<16 | int(b[3])<<8 | int(b[4])
+			flags = b[0]
+			out = append(out, string(b[5:5+n]))
+			b = b[5+n:]
+		}
+		return out, flags
+	}
+
+	resp, body := post(http1, "GetMessage", "application/json", []byte(fmt.Sprintf(`{"id":%d}`, name.Id)))
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "native") {
+		t.Errorf("connect unary: %d %s", resp.StatusCode, body)
+	}
+	resp, body = post(http1, "GetMessage", "application/json", []byte(`{"text":"nothing"}`), "Origin", "https://app.example")
+	if resp.StatusCode != http.StatusNotFound || !strings.Contains(string(body), `"code":"not_found"`) || !strings.Contains(string(body), rpcerror.ReasonMessageNotFound) ||
+		resp.Header.Get("Access-Control-Allow-Origin") != "https://app.example" {
+		t.Errorf("connect error: %d %v %s", resp.StatusCode, resp.Header, body)
+	}
+
+	req, _ := proto.Marshal(&pb.Message{Message: "grpc-web"})
+	resp, body = post(h2c, "PutMessage", "application/grpc-web+proto", envelope(0, req))
+	msgs, flags := frames(body)
+	if resp.StatusCode != http.StatusOK || len(msgs) != 2 || flags != 0x80 || !strings.Contains(msgs[1], "grpc-status: 0\r\n") {
+		t.Fatalf("grpc-web: %d %q", resp.StatusCode, body)
+	}
+	put := &pb.Name{}
+	if err := proto.Unmarshal([]byte(msgs[0]), put); err != nil || put.Id == 0 {
+		t.Errorf("grpc-web response: %v %v", put, err)
+	}
+
+	// The official browser client's default, over HTTP/1.1, with the
+	// response in one padded base64 chunk per flush.
+	fromText := func(b []byte) []byte {
+		var out []byte
+		for len(b) > 0 {
+			end := len(b)
+			if i := bytes.IndexByte(b, '='); i >= 0 {
+				end = i - i%4 + 4
+			}
+			d, err := base64.StdEncoding.DecodeString(string(b[:end]))
+			if err != nil {
+				t.Fatalf("grpc-web-text: %q: %v", b, err)
+			}
+			out, b = append(out, d...), b[end:]
+		}
+		return out
+	}
+	list, _ := proto.Marshal(&pb.Request{Number: 2, Interval: durationpb.New(0)})
+	resp, body = post(http1, "ListMessage", "application/grpc-web-text+proto", []byte(base64.StdEncoding.EncodeToString(envelope(0, list))))
+	msgs, flags = frames(fromText(body))
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/grpc-web-text+proto" ||
+		len(msgs) != 3 || flags != 0x80 || !strings.Contains(msgs[2], "grpc-status: 0\r\n") {
+		t.Fatalf("grpc-web-text: %d %v %q", resp.StatusCode, resp.Header, body)
+	}
+	sent := &pb.Message{}
+	if err := proto.Unmarshal([]byte(msgs[1]), sent); err != nil || sent.Message != "send 1" {
+		t.Errorf("grpc-web-text response: %v %v", sent, err)
+	}
+
+	resp, body = post(h2c, "ListMessage", "application/connect+json", envelope(0, []byte(`{"number":2,"interval":"0s"}`)))
+	msgs, flags = frames(body)
+	if resp.StatusCode != http.StatusOK || len(msgs) != 3 || flags != 0x02 || !strings.Contains(strings.ReplaceAll(msgs[1], " ", ""), "send1") || msgs[2] != "{}" {
+		t.Errorf("connect stream: %d %q", resp.StatusCode, body)
+	}
+	resp, body = post(h2c, "ListMessage", "application/json", []byte(`{"number":1}`))
+	if resp.StatusCode != http.StatusUnsupportedMediaType {
+		t.Errorf("connect unary on a stream: %d %s", resp.StatusCode, body)
+	}
+	var gzBody bytes.Buffer
+	zw := gz.NewWriter(&gzBody)
+	fmt.Fprintf(zw, `{"id":%d}`, name.Id)
+	zw.Close()
+	resp, body = post(http1, "GetMessage", "application/json", gzBody.Bytes(), "Content-Encoding", "gzip")
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "native") {
+		t.Errorf("connect compressed: %d %s", resp.StatusCode, body)
+	}
+
+	preflight, _ := http.NewRequest(http.MethodOptions, "http://localhost/simple.Simple/PutMessage", nil)
+	preflight.Header.Set("Origin", "https://app.example")
+	preflight.Header.Set("Access-Control-Request-Method", "POST")
+	if resp, err := http1.Do(preflight); err != nil || resp.StatusCode != http.StatusNoContent || resp.Header.Get("Access-Control-Allow-Origin") != "https://app.example" {
+		t.Errorf("preflight: %v %v", resp, err)
+	}
+}
@@ -0,0 +1,91 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	pb "github.com/shin5ok/proto-grpc-simple/pb"
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	messagesBucket = []byte("messages")
+	idsBucket      = []byte("ids")
+)
+
+// boltLockTimeout bounds the wait for another process, such as the instance
+// being replaced, to release the database file.
+const boltLockTimeout = time.Second
+
+type boltStore struct {
+	db *bolt.DB
+}
+
+func NewBolt(path string) (Store, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: boltLockTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, b := range [][]byte{messagesBucket, idsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &boltStore{db: db}, nil
+}
+
+func idKey(id int32) []byte {
+	return []byte(strconv.Itoa(int(id)))
+}
+
+func (b *boltStore) Put(ctx context.Context, name *pb.Name, message *pb.Message) error {
+	stored := proto.Clone(message).(*pb.Message)
+	stored.Name = proto.Clone(name).(*pb.Name)
+	data, err := proto.Marshal(stored)
+	if err != nil {
+		return err
+	}
+
+	return b.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(messagesBucket).Put([]byte(name.Text), data); err != nil {
+			return err
+		}
+		return tx.Bucket(idsBucket).Put(idKey(name.Id), []byte(name.Text))
+	})
+}
+
+func (b *boltStore) Get(ctx context.Context, name *pb.Name) (*pb.Message, error) {
+	var message pb.Message
+	err := b.db.View(func(tx *bolt.Tx) error {
+		key := []byte(name.Text)
+		if len(key) == 0 {
+			key = tx.Bucket(idsBucket).Get(idKey(name.Id))
+		}
+		if len(key) == 0 {
+			return ErrNotFound
+		}
+		data := tx.Bucket(messagesBucket).Get(key)
+		if data == nil {
+			return ErrNotFound
+		}
+		return proto.Unmarshal(data, &message)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+func (b *boltStore) Close() error {
+	return b.db.Close()
+}
@@ -0,0 +1,52 @@
+package store
+
+import (
+	"context"
+	"sync"
+
+	pb "github.com/shin5ok/proto-grpc-simple/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+type memory struct {
+	mu       sync.RWMutex
+	messages map[string]*pb.Message
+	ids      map[int32]string
+}
+
+func NewMemory() Store {
+	return &memory{
+		messages: map[string]*pb.Message{},
+		ids:      map[int32]string{},
+	}
+}
+
+func (m *memory) Put(ctx context.Context, name *pb.Name, message *pb.Message) error {
+	stored := proto.Clone(message).(*pb.Message)
+	stored.Name = proto.Clone(name).(*pb.Name)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages[name.Text] = stored
+	m.ids[name.Id] = name.Text
+	return nil
+}
+
+func (m *memory) Get(ctx context.Context, name *pb.Name) (*pb.Message, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	key := name.Text
+	if key == "" {
+		key = m.ids[name.Id]
+	}
+	message, ok := m.messages[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return proto.Clone(message).(*pb.Message), nil
+}
+
+func (m *memory) Close() error {
+	return nil
+}
@@ -0,0 +1,30 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	pb "github.com/shin5ok/proto-grpc-simple/pb"
+)
+
+var ErrNotFound = errors.New("message not found")
+
+// Store keeps messages under the Name assigned by PutMessage.
+// Get looks a message up by Name.Text and falls back to Name.Id when Text is empty.
+type Store interface {
+	Put(ctx context.Context, name *pb.Name, message *pb.Message) error
+	Get(ctx context.Context, name *pb.Name) (*pb.Message, error)
+	Close() error
+}
+
+// New returns the Store selected by kind, "memory" (default) or "bolt".
+func New(kind, path string) (Store, error) {
+	switch kind {
+	case "", "memory":
+		return NewMemory(), nil
+	case "bolt":
+		return NewBolt(path)
+	}
+	return nil, fmt.Errorf("unknown store type: %s", kind)
+}
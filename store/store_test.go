@@ -0,0 +1,97 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	pb "github.com/shin5ok/proto-grpc-simple/pb"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+
+	bolt, err := NewBolt(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bolt.Close()
+
+	for kind, s := range map[string]Store{
+		"memory": NewMemory(),
+		"bolt":   bolt,
+	} {
+		t.Run(kind, func(t *testing.T) {
+			name := &pb.Name{Id: 1, Text: "foo"}
+			if err := s.Put(ctx, name, &pb.Message{Message: "foo is message"}); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, key := range []*pb.Name{{Text: "foo"}, {Id: 1}} {
+				message, err := s.Get(ctx, key)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if message.Message != "foo is message" || message.Name.Text != "foo" || message.Name.Id != 1 {
+					t.Errorf("unexpected message %+v", message)
+				}
+			}
+
+			if _, err := s.Get(ctx, &pb.Name{Text: "bar"}); !errors.Is(err, ErrNotFound) {
+				t.Errorf("expected ErrNotFound, got %v", err)
+			}
+			if _, err := s.Get(ctx, &pb.Name{Id: 2}); !errors.Is(err, ErrNotFound) {
+				t.Errorf("expected ErrNotFound, got %v", err)
+			}
+		})
+	}
+}
+
+func TestBoltPersists(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "test.db")
+
+	s, err := NewBolt(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(ctx, &pb.Name{Id: 1, Text: "foo"}, &pb.Message{Message: "foo is message"}); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	s, err = NewBolt(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	message, err := s.Get(ctx, &pb.Name{Text: "foo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message.Message != "foo is message" {
+		t.Errorf("unexpected message %+v", message)
+	}
+}
+
+func TestBoltLocked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	s, err := NewBolt(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if _, err := NewBolt(path); !errors.Is(err, bolt.ErrTimeout) || !strings.Contains(err.Error(), path) {
+		t.Errorf("expected a timeout naming %s, got %v", path, err)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New("unknown", ""); err == nil {
+		t.Error("expected error for unknown store type")
+	}
+}
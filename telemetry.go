@@ -2,26 +2,75 @@ package main
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"strconv"
+	"strings"
 
 	texporter "github.com/GoogleCloudPlatform/opentelemetry-operations-go/exporter/trace"
 	gcppropagator "github.com/GoogleCloudPlatform/opentelemetry-operations-go/propagator"
 	"go.opentelemetry.io/contrib/detectors/gcp"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
 	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+
+	"github.com/shin5ok/proto-grpc-simple/config"
 )
 
-func tpExporter(projectID, serviceName string) *sdktrace.TracerProvider {
-	exporter, err := texporter.New(texporter.WithProjectID(projectID))
-	if err != nil {
-		log.Fatalf("texporter.New: %v", err)
-		return nil
+func newTraceExporter(ctx context.Context, projectID string, c config.Trace) (sdktrace.SpanExporter, error) {
+	switch c.Exporter {
+	case "cloudtrace":
+		return texporter.New(texporter.WithProjectID(projectID))
+	case "otlp-grpc":
+		return otlptracegrpc.New(ctx)
+	case "otlp-http":
+		return otlptracehttp.New(ctx)
+	case "stdout":
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case "json":
+		return stdouttrace.New()
+	case "none":
+		return nil, nil
 	}
+	return nil, fmt.Errorf("unknown trace exporter: %s", c.Exporter)
+}
 
-	ctx := context.Background()
+func newSampler(c config.Trace) (sdktrace.Sampler, error) {
+	ratio := 1.0
+	if c.SamplerArg != "" {
+		r, err := strconv.ParseFloat(c.SamplerArg, 64)
+		if err != nil || r < 0 || r > 1 {
+			return nil, fmt.Errorf("sampler ratio must be between 0 and 1: %s", c.SamplerArg)
+		}
+		ratio = r
+	}
+
+	switch c.Sampler {
+	case "always_on":
+		return sdktrace.AlwaysSample(), nil
+	case "always_off":
+		return sdktrace.NeverSample(), nil
+	case "traceidratio":
+		return sdktrace.TraceIDRatioBased(ratio), nil
+	case "parentbased_always_on":
+		return sdktrace.ParentBased(sdktrace.AlwaysSample()), nil
+	case "parentbased_always_off":
+		return sdktrace.ParentBased(sdktrace.NeverSample()), nil
+	case "parentbased_traceidratio":
+		return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio)), nil
+	}
+	return nil, fmt.Errorf("unknown sampler: %s", c.Sampler)
+}
+
+func newResource(ctx context.Context, serviceName string) (*resource.Resource, error) {
 	res, err := resource.New(ctx,
 		resource.WithDetectors(gcp.NewDetector()),
 		resource.WithTelemetrySDK(),
@@ -30,15 +79,36 @@ func tpExporter(projectID, serviceName string) *sdktrace.TracerProvider {
 		),
 	)
 	if err != nil {
-		log.Fatalf("resource.New: %v", err)
-		return nil
+		return nil, fmt.Errorf("resource.New: %w", err)
+	}
+	return res, nil
+}
+
+func tpExporter(projectID string, c config.Trace) (*sdktrace.TracerProvider, error) {
+	ctx := context.Background()
+
+	exporter, err := newTraceExporter(ctx, projectID, c)
+	if err != nil {
+		return nil, fmt.Errorf("trace exporter %s: %w", c.Exporter, err)
+	}
+	sampler, err := newSampler(c)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := newResource(ctx, c.ServiceName)
+	if err != nil {
+		return nil, err
 	}
 
-	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(exporter),
+	opts := []sdktrace.TracerProviderOption{
 		sdktrace.WithResource(res),
-		sdktrace.WithSampler(sdktrace.AlwaysSample()),
-	)
+		sdktrace.WithSampler(sampler),
+	}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+	tp := sdktrace.NewTracerProvider(opts...)
 
 	func() {
 		otel.SetTextMapPropagator(
@@ -48,5 +118,42 @@ func tpExporter(projectID, serviceName string) *sdktrace.TracerProvider {
 				propagation.Baggage{},
 			))
 	}()
-	return tp
+	return tp, nil
+}
+
+func mpExporter(serviceName string, c config.Metrics) (*sdkmetric.MeterProvider, error) {
+	ctx := context.Background()
+
+	res, err := newResource(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	opts := []sdkmetric.Option{sdkmetric.WithResource(res)}
+
+	for _, name := range c.Exporters {
+		var exporter sdkmetric.Exporter
+		switch strings.TrimSpace(name) {
+		case "prometheus":
+			reader, err := otelprometheus.New()
+			if err != nil {
+				return nil, fmt.Errorf("metric exporter prometheus: %w", err)
+			}
+			opts = append(opts, sdkmetric.WithReader(reader))
+			continue
+		case "otlp-grpc":
+			exporter, err = otlpmetricgrpc.New(ctx)
+		case "otlp-http":
+			exporter, err = otlpmetrichttp.New(ctx)
+		case "none", "":
+			continue
+		default:
+			return nil, fmt.Errorf("unknown metric exporter: %s", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("metric exporter %s: %w", name, err)
+		}
+		opts = append(opts, sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(c.Interval))))
+	}
+
+	return sdkmetric.NewMeterProvider(opts...), nil
 }
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shin5ok/proto-grpc-simple/config"
+)
+
+func TestNewSampler(t *testing.T) {
+	for _, c := range []struct {
+		sampler, arg string
+		ok           bool
+	}{
+		{"always_on", "", true},
+		{"always_off", "", true},
+		{"traceidratio", "0.5", true},
+		{"parentbased_always_on", "", true},
+		{"parentbased_traceidratio", "0.1", true},
+		{"traceidratio", "2", false},
+		{"traceidratio", "half", false},
+		{"sometimes", "", false},
+	} {
+		_, err := newSampler(config.Trace{Sampler: c.sampler, SamplerArg: c.arg})
+		if (err == nil) != c.ok {
+			t.Errorf("%s(%s): unexpected error %v", c.sampler, c.arg, err)
+		}
+	}
+}
+
+func TestTpExporter(t *testing.T) {
+	for _, exporter := range []string{"none", "json"} {
+		tp, err := tpExporter("", config.Trace{Exporter: exporter, ServiceName: "test", Sampler: "always_on"})
+		if err != nil {
+			t.Fatalf("%s: %v", exporter, err)
+		}
+		if err := tp.Shutdown(context.Background()); err != nil {
+			t.Error(err)
+		}
+	}
+
+	if _, err := tpExporter("", config.Trace{Exporter: "zipkin", Sampler: "always_on"}); err == nil {
+		t.Error("expected error for unknown exporter")
+	}
+}
@@ -0,0 +1,155 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Files names the PEM files a server serves TLS from. With ClientCAFile,
+// client certificates are checked against it as ClientAuth says, which
+// defaults to tls.RequireAndVerifyClientCert.
+type Files struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+	ClientAuth   tls.ClientAuthType
+}
+
+// checkInterval bounds how often the files are checked for changes.
+var checkInterval = time.Second
+
+// Reloader hands out the certificate and client CAs read from Files, and
+// reads them again when the modification time or size of a file changes.
+// A reload that fails is logged and the previous files stay in use.
+type Reloader struct {
+	files  Files
+	logger zerolog.Logger
+
+	mu      sync.Mutex
+	checked time.Time
+	stamp   string
+	config  *tls.Config
+}
+
+func New(files Files, logger zerolog.Logger) (*Reloader, error) {
+	if files.ClientCAFile != "" && files.ClientAuth == tls.NoClientCert {
+		files.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	r := &Reloader{files: files, logger: logger}
+	stamp, err := r.stat()
+	if err != nil {
+		return nil, err
+	}
+	config, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	r.stamp, r.config, r.checked = stamp, config, time.Now()
+	return r, nil
+}
+
+// Config returns a tls.Config that picks up the current files on every handshake.
+func (r *Reloader) Config() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: []string{"h2"},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.current(), nil
+		},
+	}
+}
+
+func (r *Reloader) current() *tls.Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checked) < checkInterval {
+		return r.config
+	}
+	r.checked = time.Now()
+
+	stamp, err := r.stat()
+	if err != nil {
+		r.logger.Error().Msgf("tls files: %v", err)
+		return r.config
+	}
+	if stamp == r.stamp {
+		return r.config
+	}
+	config, err := r.load()
+	if err != nil {
+		r.logger.Error().Msgf("tls reload: %v", err)
+		return r.config
+	}
+	r.stamp, r.config = stamp, config
+	r.logger.Info().Str("cert", r.files.CertFile).Msg("tls certificate reloaded")
+	return r.config
+}
+
+func (r *Reloader) stat() (string, error) {
+	var stamp string
+	for _, name := range []string{r.files.CertFile, r.files.KeyFile, r.files.ClientCAFile} {
+		if name == "" {
+			continue
+		}
+		fi, err := os.Stat(name)
+		if err != nil {
+			return "", err
+		}
+		stamp += fmt.Sprintf("%s:%d:%d;", name, fi.ModTime().UnixNano(), fi.Size())
+	}
+	return stamp, nil
+}
+
+func (r *Reloader) load() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(r.files.CertFile, r.files.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		NextProtos:   []string{"h2"},
+		Certificates: []tls.Certificate{cert},
+	}
+	if r.files.ClientCAFile != "" {
+		pool, err := CertPool(r.files.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = r.files.ClientAuth
+	}
+	return config, nil
+}
+
+// CertPool reads the PEM certificates in file into a pool.
+func CertPool(file string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates in %s", file)
+	}
+	return pool, nil
+}
+
+// ParseClientAuth maps the configured client auth mode, request or require, to its tls value.
+func ParseClientAuth(s string) (tls.ClientAuthType, error) {
+	switch s {
+	case "":
+		return tls.NoClientCert, nil
+	case "request":
+		return tls.VerifyClientCertIfGiven, nil
+	case "require":
+		return tls.RequireAndVerifyClientCert, nil
+	}
+	return tls.NoClientCert, fmt.Errorf("unknown client auth: %s", s)
+}
@@ -0,0 +1,193 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type pair struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// issue creates a certificate for name signed by parent, or self-signed when parent is nil.
+func issue(t *testing.T, name string, serial int64, parent *pair) *pair {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	signer := &pair{template, key}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		signer = parent
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer.cert, &key.PublicKey, signer.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &pair{cert, key}
+}
+
+func (p *pair) write(t *testing.T, dir, name string) (string, string) {
+	certFile := filepath.Join(dir, name+".pem")
+	keyFile := filepath.Join(dir, name+"-key.pem")
+	der, _ := x509.MarshalECPrivateKey(p.key)
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: p.cert.Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func (p *pair) tls() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{p.cert.Raw}, PrivateKey: p.key}
+}
+
+// handshake connects to a TLS listener serving config and returns the server certificate.
+func handshake(t *testing.T, config *tls.Config, client *tls.Config) (*x509.Certificate, error) {
+	l, err := tls.Listen("tcp", "127.0.0.1:0", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		c, err := l.Accept()
+		if err != nil {
+			return
+		}
+		c.(*tls.Conn).Handshake()
+		c.Read(make([]byte, 1))
+		c.Close()
+	}()
+
+	conn, err := tls.Dial("tcp", l.Addr().String(), client)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	// With TLS 1.3 a rejected client certificate only shows on the first read.
+	conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	if _, err := conn.Read(make([]byte, 1)); err != nil {
+		if ne, ok := err.(net.Error); !ok || !ne.Timeout() {
+			return nil, err
+		}
+	}
+	return conn.ConnectionState().PeerCertificates[0], nil
+}
+
+func TestReload(t *testing.T) {
+	defer func(d time.Duration) { checkInterval = d }(checkInterval)
+	checkInterval = 0
+
+	dir := t.TempDir()
+	ca := issue(t, "ca", 1, nil)
+	caFile, _ := ca.write(t, dir, "ca")
+	certFile, keyFile := issue(t, "localhost", 2, ca).write(t, dir, "server")
+
+	r, err := New(Files{CertFile: certFile, KeyFile: keyFile}, zerolog.Nop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots, _ := CertPool(caFile)
+	client := &tls.Config{RootCAs: roots, ServerName: "localhost"}
+
+	cert, err := handshake(t, r.Config(), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert.SerialNumber.Int64() != 2 {
+		t.Fatalf("got serial %d", cert.SerialNumber)
+	}
+
+	issue(t, "localhost", 3, ca).write(t, dir, "server")
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(certFile, later, later)
+
+	cert, err = handshake(t, r.Config(), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert.SerialNumber.Int64() != 3 {
+		t.Errorf("not reloaded, got serial %d", cert.SerialNumber)
+	}
+
+	os.WriteFile(certFile, []byte("garbage"), 0o600)
+	os.Chtimes(certFile, later.Add(time.Minute), later.Add(time.Minute))
+	cert, err = handshake(t, r.Config(), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert.SerialNumber.Int64() != 3 {
+		t.Errorf("a broken file replaced the certificate, got serial %d", cert.SerialNumber)
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := issue(t, "ca", 1, nil)
+	caFile, _ := ca.write(t, dir, "ca")
+	certFile, keyFile := issue(t, "localhost", 2, ca).write(t, dir, "server")
+	roots, _ := CertPool(caFile)
+
+	r, err := New(Files{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile}, zerolog.Nop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &tls.Config{RootCAs: roots, ServerName: "localhost"}
+	if _, err := handshake(t, r.Config(), client); err == nil {
+		t.Error("handshake without a client certificate succeeded")
+	}
+
+	client.Certificates = []tls.Certificate{issue(t, "client", 3, ca).tls()}
+	if _, err := handshake(t, r.Config(), client); err != nil {
+		t.Error(err)
+	}
+
+	client.Certificates = []tls.Certificate{issue(t, "stranger", 4, issue(t, "other ca", 5, nil)).tls()}
+	if _, err := handshake(t, r.Config(), client); err == nil {
+		t.Error("handshake with a certificate from another CA succeeded")
+	}
+}
+
+func TestParseClientAuth(t *testing.T) {
+	for s, want := range map[string]tls.ClientAuthType{
+		"":        tls.NoClientCert,
+		"request": tls.VerifyClientCertIfGiven,
+		"require": tls.RequireAndVerifyClientCert,
+	} {
+		if got, err := ParseClientAuth(s); err != nil || got != want {
+			t.Errorf("%q: got %v, %v", s, got, err)
+		}
+	}
+	if _, err := ParseClientAuth("always"); err == nil {
+		t.Error("expected an error")
+	}
+}
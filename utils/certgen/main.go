@@ -0,0 +1,94 @@
+// certgen writes a throwaway CA with server and client certificates for
+// trying the TLS modes of the server locally, plus broken variants to see how
+// clients react to them:
+//
+//	ca.pem                  the CA for -ca-file and TLS_CLIENT_CA_FILE
+//	server.pem              valid for -hosts
+//	server-expired.pem      expired yesterday
+//	server-wrong-san.pem    valid, but only for -wrong-host
+//	server-untrusted.pem    valid for -hosts, signed by a CA nobody trusts
+//	client.pem              client certificate for mutual TLS
+//	client-expired.pem      expired client certificate
+//
+// Every certificate comes with its key in the matching -key.pem file.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/shin5ok/proto-grpc-simple/certgen"
+)
+
+func main() {
+	out := flag.String("out", "certs", "directory to write the files to")
+	hosts := flag.String("hosts", "localhost,127.0.0.1,::1", "comma separated DNS names and IPs of the server certificates")
+	wrongHost := flag.String("wrong-host", "wrong.example.com", "only SAN of server-wrong-san.pem")
+	clientCN := flag.String("client-cn", "client", "common name of the client certificates")
+	validFor := flag.Duration("valid-for", 30*24*time.Hour, "validity of the certificates that are not expired")
+	flag.Parse()
+
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		log.Fatal(err)
+	}
+	serverHosts := strings.Split(*hosts, ",")
+	now := time.Now()
+	valid := certgen.Options{NotBefore: now.Add(-time.Minute), NotAfter: now.Add(*validFor)}
+	expired := certgen.Options{NotBefore: now.Add(-30 * 24 * time.Hour), NotAfter: now.Add(-24 * time.Hour)}
+
+	ca, err := certgen.NewCA(with(valid, "simple test CA", nil, false))
+	if err != nil {
+		log.Fatal(err)
+	}
+	untrustedCA, err := certgen.NewCA(with(valid, "untrusted CA", nil, false))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	write := func(name string, c *certgen.Cert) {
+		certFile := filepath.Join(*out, name+".pem")
+		keyFile := filepath.Join(*out, name+"-key.pem")
+		if err := c.Write(certFile, keyFile); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%-34s %s, %s until %s\n", certFile, c.Cert.Subject, strings.Join(sans(c), ","), c.Cert.NotAfter.Format(time.RFC3339))
+	}
+	issue := func(name string, ca *certgen.Cert, o certgen.Options) {
+		c, err := ca.Issue(o)
+		if err != nil {
+			log.Fatal(err)
+		}
+		write(name, c)
+	}
+
+	write("ca", ca)
+	issue("server", ca, with(valid, serverHosts[0], serverHosts, false))
+	issue("server-expired", ca, with(expired, serverHosts[0], serverHosts, false))
+	issue("server-wrong-san", ca, with(valid, *wrongHost, []string{*wrongHost}, false))
+	issue("server-untrusted", untrustedCA, with(valid, serverHosts[0], serverHosts, false))
+	issue("client", ca, with(valid, *clientCN, nil, true))
+	issue("client-expired", ca, with(expired, *clientCN, nil, true))
+}
+
+func with(o certgen.Options, cn string, hosts []string, client bool) certgen.Options {
+	o.CommonName = cn
+	o.Hosts = hosts
+	o.Client = client
+	return o
+}
+
+func sans(c *certgen.Cert) []string {
+	names := append([]string{}, c.Cert.DNSNames...)
+	for _, ip := range c.Cert.IPAddresses {
+		names = append(names, ip.String())
+	}
+	if len(names) == 0 {
+		names = []string{"no SANs"}
+	}
+	return names
+}
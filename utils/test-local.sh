@@ -1,4 +1,68 @@
 grpcurl -plaintext -d '{"id":100}' localhost:8080 simple.Simple.GetMessage
 grpcurl -plaintext -d '{"message":"foo"}' localhost:8080 simple.Simple.PutMessage
-grpcurl -plaintext localhost:8080 simple.Simple.PingPong
+grpcurl -plaintext -proto proto/simple.proto localhost:8080 simple.Simple.PingPong
 grpcurl -plaintext localhost:8080 simple.Simple.ListMessage
+grpcurl -plaintext -H "x-chat-mode: fanout" -d '{"message":"foo"}' localhost:8080 simple.Simple.Chat
+
+grpcurl -plaintext -d '{"service":"simple.Simple"}' localhost:8080 grpc.health.v1.Health.Check
+curl -X POST "localhost:18080/admin/health?service=simple.Simple&status=NOT_SERVING"
+curl -X POST "localhost:18080/admin/health?service=simple.Simple&status=SERVING"
+
+# with FAULT_INJECTION=true
+grpcurl -plaintext -H "x-fault-code: UNAVAILABLE" -H "x-fault-percent: 50" localhost:8080 simple.Simple.PingPong
+grpcurl -plaintext -H "x-fault-after-n-messages: 3" -d '{"number":10}' localhost:8080 simple.Simple.ListMessage
+
+# with TLS_CERT_FILE=certs/server.pem TLS_KEY_FILE=certs/server-key.pem TLS_CLIENT_CA_FILE=certs/ca.pem
+go run ./utils/certgen -out certs
+go run ./clients/golang -host localhost:8080 -ca-file certs/ca.pem -cert-file certs/client.pem -key-file certs/client-key.pem -server-name localhost
+# each of these fails: server-expired, server-wrong-san and server-untrusted as the server certificate, client-expired as the client one
+
+# with AUTH_API_KEYS_FILE=keys.yaml containing [{name: ci, key: secret, scopes: [messages.write]}]
+# and AUTH_POLICY="PingPong=public,PutMessage=scope:messages.write"
+grpcurl -plaintext -proto proto/simple.proto localhost:8080 simple.Simple.PingPong
+grpcurl -plaintext -H "x-api-key: secret" -d '{"message":"foo"}' localhost:8080 simple.Simple.PutMessage
+go run ./clients/golang -host localhost:8080 -insecure -mode put-message -api-key secret
+
+# with LIMIT_METHODS="PingPong=1/2" LIMIT_MAX_STREAM_MESSAGES=5: the third call and the sixth message get RESOURCE_EXHAUSTED
+for i in 1 2 3; do grpcurl -plaintext localhost:8080 simple.Simple.PingPong; done
+grpcurl -plaintext -d '{"number":10}' localhost:8080 simple.Simple.ListMessage
+
+# with VALIDATE=true: rejected with INVALID_ARGUMENT and BadRequest field violations; VALIDATE_BOUNDS="Request.number=100" lowers the bound
+grpcurl -plaintext -d '{"number":100000}' localhost:8080 simple.Simple.ListMessage
+grpcurl -plaintext -d '{}' localhost:8080 simple.Simple.PutMessage
+
+# every error carries google.rpc.ErrorInfo (reason MESSAGE_NOT_FOUND here) and RequestInfo with the trace id;
+# with DEBUG_ERRORS=true, failures also carry DebugInfo with the underlying error
+grpcurl -plaintext -d '{"id":12345}' localhost:8080 simple.Simple.GetMessage
+go run ./clients/golang -host localhost:8080 -insecure -mode list-message -number 100000
+
+# with GATEWAY_PORT=8081: the same service as HTTP/JSON
+curl -XPOST localhost:8081/v1/messages -d '{"message":"hello"}'
+curl localhost:8081/v1/messages/1
+curl "localhost:8081/v1/messages:list?number=3&interval=0.5s"
+curl -H "Accept: text/event-stream" "localhost:8081/v1/messages:list?number=3"
+curl -XPOST localhost:8081/v2/messages:bulkPut -d '{"message":"a"} {"message":"b"}'
+printf '{"message":"a"}\n{"message":"b"}\n' | curl -XPOST localhost:8081/v1/messages:bulkPut --data-binary @-
+
+# with WEB=true: gRPC-Web and Connect on the main port
+curl -XPOST localhost:8080/simple.Simple/PutMessage -H "Content-Type: application/json" -d '{"message":"hello"}'
+curl -XPOST localhost:8080/simple.Simple/GetMessage -H "Content-Type: application/json" -d '{"id":1}'
+printf '\x00\x00\x00\x00\x0c{"number":3}' | curl -XPOST localhost:8080/simple.Simple/ListMessage -H "Content-Type: application/connect+json" --data-binary @- --output -
+printf '\x00\x00\x00\x00\x02\x08\x01' | base64 | curl -XPOST localhost:8080/simple.Simple/GetMessage -H "Content-Type: application/grpc-web-text" --data-binary @-
+
+# with SINGLE_PORT=true: everything on 8080
+curl localhost:8080/metrics
+curl localhost:8080/admin/health
+curl localhost:8080/v1/messages/1
+# and SINGLE_PORT_ADMIN=true
+curl -X POST "localhost:8080/admin/health?service=simple.Simple&status=SERVING"
+
+# with DEBUG_ADMIN=true DEBUG_PPROF=true DEBUG_CONFIG=true
+grpcurl -plaintext localhost:8080 grpc.channelz.v1.Channelz/GetServers
+curl "localhost:18080/debug/pprof/goroutine?debug=2"
+curl localhost:18080/debug/config
+
+# with GRPC_MAX_CONNECTION_AGE=10s GRPC_MAX_CONNECTION_AGE_GRACE=5s: streams are cut with GOAWAY
+go run ./clients/golang -host localhost:8080 -insecure -mode list-message -number 100
+# with GRPC_MAX_SEND_MSG_SIZE=1024: ResourceExhausted
+go run ./clients/golang -host localhost:8080 -insecure -mode list-message -number 1 -payload-size 2048
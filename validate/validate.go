@@ -0,0 +1,281 @@
+// Package validate checks requests against the (simple.validate.field) and
+// (simple.validate.method) options of proto/validate.proto.
+package validate
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	pb "github.com/shin5ok/proto-grpc-simple/pb"
+	"github.com/shin5ok/proto-grpc-simple/rpcerror"
+)
+
+// Validator checks messages against their field rules, with upper bounds
+// replaced by the configured ones.
+type Validator struct {
+	// bounds replace lte or max_len by field; durations are in nanoseconds.
+	bounds map[protoreflect.FullName]int64
+	// methods caches the rules of each method by full method name.
+	methods sync.Map
+	// handled are the methods whose handlers check the messages streamed to them.
+	handled map[string]bool
+}
+
+// New parses bounds, entries "Message.field=value" with the message named
+// relative to package (e.g. "Request.number=100" for simple.Request.number)
+// or in full. Values are numbers, or durations for Duration fields.
+func New(pkg string, bounds []string) (*Validator, error) {
+	v := &Validator{bounds: map[protoreflect.FullName]int64{}, handled: map[string]bool{}}
+	for _, b := range bounds {
+		name, value, ok := strings.Cut(b, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid bound %q: want Message.field=value", b)
+		}
+		fd, err := findField(pkg, strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		n, err := parseBound(fd, strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("bound for %s: %w", fd.FullName(), err)
+		}
+		v.bounds[fd.FullName()] = n
+	}
+	return v, nil
+}
+
+func findField(pkg, name string) (protoreflect.FieldDescriptor, error) {
+	i := strings.LastIndex(name, ".")
+	if i < 0 {
+		return nil, fmt.Errorf("invalid field %q: want Message.field", name)
+	}
+	msgName := protoreflect.FullName(name[:i])
+	if pkg != "" && !strings.HasPrefix(name, pkg+".") {
+		msgName = protoreflect.FullName(pkg + "." + name[:i])
+	}
+	md, err := protoregistry.GlobalFiles.FindDescriptorByName(msgName)
+	if err != nil {
+		return nil, fmt.Errorf("unknown message %s", msgName)
+	}
+	m, ok := md.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a message", msgName)
+	}
+	fd := m.Fields().ByName(protoreflect.Name(name[i+1:]))
+	if fd == nil {
+		return nil, fmt.Errorf("unknown field %s.%s", msgName, name[i+1:])
+	}
+	return fd, nil
+}
+
+func parseBound(fd protoreflect.FieldDescriptor, value string) (int64, error) {
+	if isDuration(fd) {
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return 0, fmt.Errorf("not a duration: %q", value)
+		}
+		return int64(d), nil
+	}
+	switch fd.Kind() {
+	case protoreflect.Int32Kind, protoreflect.StringKind, protoreflect.BytesKind:
+	default:
+		return 0, fmt.Errorf("fields of kind %s have no bound", fd.Kind())
+	}
+	n, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("not a number: %q", value)
+	}
+	return n, nil
+}
+
+func isDuration(fd protoreflect.FieldDescriptor) bool {
+	return fd.Message() != nil && fd.Message().FullName() == "google.protobuf.Duration"
+}
+
+// Violations lists the field violations of m. required names top-level fields
+// that must be set on top of the field rules.
+func (v *Validator) Violations(m proto.Message, required []string) []*errdetails.BadRequest_FieldViolation {
+	r := m.ProtoReflect()
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, name := range required {
+		fd := r.Descriptor().Fields().ByName(protoreflect.Name(name))
+		if fd != nil && !r.Has(fd) {
+			violations = append(violations, violation(name, "is required"))
+		}
+	}
+	return append(violations, v.message(r, "")...)
+}
+
+func (v *Validator) message(m protoreflect.Message, prefix string) []*errdetails.BadRequest_FieldViolation {
+	var violations []*errdetails.BadRequest_FieldViolation
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		path := prefix + string(fd.Name())
+		rules, _ := proto.GetExtension(fd.Options(), pb.E_Field).(*pb.FieldRules)
+
+		if rules.GetRequired() && !m.Has(fd) {
+			violations = append(violations, violation(path, "is required"))
+			continue
+		}
+		switch {
+		case fd.IsList() && fd.Message() != nil:
+			list := m.Get(fd).List()
+			for j := 0; j < list.Len(); j++ {
+				violations = append(violations, v.message(list.Get(j).Message(), fmt.Sprintf("%s[%d].", path, j))...)
+			}
+		case fd.IsList() || fd.IsMap():
+		case rules != nil && (fd.Message() == nil || m.Has(fd)):
+			violations = append(violations, v.field(fd, rules, m.Get(fd), path)...)
+		case fd.Message() != nil && m.Has(fd) && !isDuration(fd):
+			violations = append(violations, v.message(m.Get(fd).Message(), path+".")...)
+		}
+	}
+	return violations
+}
+
+func (v *Validator) field(fd protoreflect.FieldDescriptor, rules *pb.FieldRules, value protoreflect.Value, path string) []*errdetails.BadRequest_FieldViolation {
+	var violations []*errdetails.BadRequest_FieldViolation
+	add := func(format string, args ...interface{}) {
+		violations = append(violations, violation(path, fmt.Sprintf(format, args...)))
+	}
+	bound, hasBound := v.bounds[fd.FullName()]
+
+	switch t := rules.Type.(type) {
+	case *pb.FieldRules_Int32:
+		n := int64(value.Int())
+		if t.Int32.Gte != nil && n < int64(t.Int32.GetGte()) {
+			add("must be at least %d", t.Int32.GetGte())
+		}
+		if max, ok := upper(t.Int32.Lte != nil, int64(t.Int32.GetLte()), bound, hasBound); ok && n > max {
+			add("must be at most %d", max)
+		}
+	case *pb.FieldRules_String_:
+		n := int64(utf8.RuneCountInString(value.String()))
+		if t.String_.MinLen != nil && n < int64(t.String_.GetMinLen()) {
+			add("must be at least %d characters", t.String_.GetMinLen())
+		}
+		if max, ok := upper(t.String_.MaxLen != nil, int64(t.String_.GetMaxLen()), bound, hasBound); ok && n > max {
+			add("must be at most %d characters", max)
+		}
+	case *pb.FieldRules_Bytes:
+		n := int64(len(value.Bytes()))
+		if max, ok := upper(t.Bytes.MaxLen != nil, int64(t.Bytes.GetMaxLen()), bound, hasBound); ok && n > max {
+			add("must be at most %d bytes", max)
+		}
+	case *pb.FieldRules_Duration:
+		d, ok := value.Message().Interface().(*durationpb.Duration)
+		if !ok {
+			break
+		}
+		if err := d.CheckValid(); err != nil {
+			add("is not a valid duration")
+			break
+		}
+		if t.Duration.Gte != nil && d.AsDuration() < t.Duration.Gte.AsDuration() {
+			add("must be at least %s", t.Duration.Gte.AsDuration())
+		}
+		if max, ok := upper(t.Duration.Lte != nil, int64(t.Duration.Lte.AsDuration()), bound, hasBound); ok && d.AsDuration() > time.Duration(max) {
+			add("must be at most %s", time.Duration(max))
+		}
+	}
+	return violations
+}
+
+// upper returns the configured bound if there is one, else the rule's.
+func upper(hasRule bool, rule, bound int64, hasBound bool) (int64, bool) {
+	if hasBound {
+		return bound, true
+	}
+	return rule, hasRule
+}
+
+func violation(field, description string) *errdetails.BadRequest_FieldViolation {
+	return &errdetails.BadRequest_FieldViolation{Field: field, Description: field + " " + description}
+}
+
+// Error returns an InvalidArgument status carrying the violations as BadRequest.
+func Error(msg string, violations []*errdetails.BadRequest_FieldViolation) error {
+	descriptions := make([]string, len(violations))
+	for i, fv := range violations {
+		descriptions[i] = fv.Description
+	}
+	return rpcerror.New(codes.InvalidArgument, rpcerror.ReasonInvalidRequest, msg+": "+strings.Join(descriptions, "; "),
+		rpcerror.WithDetails(&errdetails.BadRequest{FieldViolations: violations}))
+}
+
+// LeaveToHandler leaves the messages streamed to the methods unchecked, for
+// handlers that check each one with Violations and report it themselves. It
+// must be called before serving.
+func (v *Validator) LeaveToHandler(fullMethods ...string) {
+	for _, m := range fullMethods {
+		v.handled[m] = true
+	}
+}
+
+// Required returns the required fields of the method, from its (simple.validate.method) option.
+func (v *Validator) Required(fullMethod string) []string {
+	if r, ok := v.methods.Load(fullMethod); ok {
+		return r.([]string)
+	}
+	var required []string
+	name := protoreflect.FullName(strings.ReplaceAll(strings.TrimPrefix(fullMethod, "/"), "/", "."))
+	if d, err := protoregistry.GlobalFiles.FindDescriptorByName(name); err == nil {
+		if md, ok := d.(protoreflect.MethodDescriptor); ok {
+			rules, _ := proto.GetExtension(md.Options(), pb.E_Method).(*pb.MethodRules)
+			required = rules.GetRequired()
+		}
+	}
+	v.methods.Store(fullMethod, required)
+	return required
+}
+
+func (v *Validator) UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if m, ok := req.(proto.Message); ok {
+		if violations := v.Violations(m, v.Required(info.FullMethod)); len(violations) > 0 {
+			return nil, Error("invalid request", violations)
+		}
+	}
+	return handler(ctx, req)
+}
+
+func (v *Validator) StreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if v.handled[info.FullMethod] {
+		return handler(srv, ss)
+	}
+	return handler(srv, &validatingStream{ServerStream: ss, v: v, required: v.Required(info.FullMethod)})
+}
+
+// validatingStream checks every message it receives, failing the stream on the first invalid one.
+type validatingStream struct {
+	grpc.ServerStream
+	v        *Validator
+	required []string
+	received int
+}
+
+func (s *validatingStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	s.received++
+	if msg, ok := m.(proto.Message); ok {
+		if violations := s.v.Violations(msg, s.required); len(violations) > 0 {
+			return Error(fmt.Sprintf("invalid message %d", s.received-1), violations)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package validate
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	pb "github.com/shin5ok/proto-grpc-simple/pb"
+	"github.com/shin5ok/proto-grpc-simple/rpcerror"
+)
+
+func fields(violations []*errdetails.BadRequest_FieldViolation) string {
+	f := make([]string, len(violations))
+	for i, v := range violations {
+		f[i] = v.Field
+	}
+	return strings.Join(f, ",")
+}
+
+func TestViolations(t *testing.T) {
+	v, err := New("simple", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		m        proto.Message
+		required []string
+		want     string
+	}{
+		{&pb.Request{Number: 10, Interval: durationpb.New(time.Second)}, nil, ""},
+		{&pb.Request{Number: -1}, nil, "number"},
+		{&pb.Request{Number: 10001, PayloadSize: -5}, nil, "number,payload_size"},
+		{&pb.Request{Interval: durationpb.New(-time.Second), Jitter: durationpb.New(2 * time.Hour)}, nil, "interval,jitter"},
+		{&pb.Request{InitialDelay: &durationpb.Duration{Seconds: 1, Nanos: -1}}, nil, "initial_delay"},
+		{&pb.Message{}, nil, ""},
+		{&pb.Message{}, []string{"message"}, "message"},
+		{&pb.Message{Message: strings.Repeat("あ", 4096)}, []string{"message"}, ""},
+		{&pb.Message{Message: strings.Repeat("a", 4097)}, nil, "message"},
+		{&pb.Message{Payload: make([]byte, 1<<20+1)}, nil, "payload"},
+		{&pb.Message{Name: &pb.Name{Id: -1, Text: strings.Repeat("a", 65)}}, nil, "name.id,name.text"},
+		{&pb.BulkPutSummary{Names: []*pb.Name{{Id: 1}, {Id: -1}}}, nil, "names[1].id"},
+	} {
+		if got := fields(v.Violations(c.m, c.required)); got != c.want {
+			t.Errorf("%v: got %q, want %q", c.m, got, c.want)
+		}
+	}
+}
+
+func TestBounds(t *testing.T) {
+	v, err := New("simple", []string{"Request.number=5", "simple.Message.message=3", "Request.interval=10s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		m    proto.Message
+		want string
+	}{
+		{&pb.Request{Number: 5, Interval: durationpb.New(10 * time.Second)}, ""},
+		{&pb.Request{Number: 6, Interval: durationpb.New(11 * time.Second)}, "number,interval"},
+		{&pb.Request{Number: -1}, "number"},
+		{&pb.Message{Message: "abcd"}, "message"},
+	} {
+		if got := fields(v.Violations(c.m, nil)); got != c.want {
+			t.Errorf("%v: got %q, want %q", c.m, got, c.want)
+		}
+	}
+
+	for _, bad := range []string{"Request.number", "Request.count=1", "Nothing.number=1", "Request.number=many", "Request.interval=5", "Message.name=1"} {
+		if _, err := New("simple", []string{bad}); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	v, _ := New("simple", nil)
+	info := &grpc.UnaryServerInfo{FullMethod: "/simple.Simple/PutMessage"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return req, nil }
+
+	if _, err := v.UnaryServerInterceptor(context.Background(), &pb.Message{Message: "foo"}, info, handler); err != nil {
+		t.Error(err)
+	}
+	_, err := v.UnaryServerInterceptor(context.Background(), &pb.Message{}, info, handler)
+	d := rpcerror.Decode(err)
+	if d.Code != codes.InvalidArgument || d.Reason() != rpcerror.ReasonInvalidRequest {
+		t.Fatalf("got %v", d)
+	}
+	if fields(d.BadRequest.GetFieldViolations()) != "message" {
+		t.Errorf("got %v", d)
+	}
+
+	// PingPong needs no message.
+	info.FullMethod = "/simple.Simple/PingPong"
+	if _, err := v.UnaryServerInterceptor(context.Background(), &pb.Message{}, info, handler); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,51 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// exposedHeaders are the response headers browsers let gRPC-Web and Connect
+// clients read, on top of the CORS-safelisted ones.
+var exposedHeaders = strings.Join([]string{
+	"Grpc-Status", "Grpc-Message", "Grpc-Status-Details-Bin",
+	"Content-Encoding", "Connect-Content-Encoding",
+}, ", ")
+
+// CORS lets pages on origins call next from browsers. An origin of "*"
+// allows any. Preflight requests are answered here; requests from other
+// origins get no CORS headers, so browsers keep their responses from pages.
+func CORS(origins []string, maxAge time.Duration, next http.Handler) http.Handler {
+	allowed := map[string]bool{}
+	for _, o := range origins {
+		allowed[strings.TrimSuffix(o, "/")] = true
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		ok := origin != "" && (allowed["*"] || allowed[origin])
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+		w.Header().Add("Vary", "Origin")
+		if ok {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Expose-Headers", exposedHeaders)
+		}
+		if !preflight {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if ok {
+			w.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS")
+			// Requests carry metadata as headers of any name.
+			if h := r.Header.Get("Access-Control-Request-Headers"); h != "" {
+				w.Header().Set("Access-Control-Allow-Headers", h)
+			}
+			if maxAge > 0 {
+				w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(maxAge.Seconds())))
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,17 @@
+package web
+
+import (
+	"net/http"
+	"strings"
+)
+
+// IsGRPC tells whether r is a gRPC request.
+func IsGRPC(r *http.Request) bool {
+	return IsGRPCContentType(r.Header.Get("Content-Type"))
+}
+
+// IsGRPCContentType tells a gRPC content type from gRPC-Web and others.
+func IsGRPCContentType(contentType string) bool {
+	rest := strings.TrimPrefix(contentType, "application/grpc")
+	return rest != contentType && (rest == "" || rest[0] == '+' || rest[0] == ';')
+}
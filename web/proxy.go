@@ -0,0 +1,206 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/shin5ok/proto-grpc-simple/gateway"
+	pb "github.com/shin5ok/proto-grpc-simple/pb"
+)
+
+// proxy serves the Simple service by calling it on a gRPC connection, with
+// the headers of requests passed on as metadata and the response metadata
+// passed back as headers and trailers.
+type proxy struct {
+	client pb.SimpleClient
+}
+
+func (p *proxy) GetMessage(ctx context.Context, req *connect.Request[pb.Name]) (*connect.Response[pb.Message], error) {
+	return unary(ctx, req, p.client.GetMessage)
+}
+
+func (p *proxy) PutMessage(ctx context.Context, req *connect.Request[pb.Message]) (*connect.Response[pb.Name], error) {
+	return unary(ctx, req, p.client.PutMessage)
+}
+
+func (p *proxy) PingPong(ctx context.Context, req *connect.Request[pb.Message]) (*connect.Response[pb.Message], error) {
+	return unary(ctx, req, p.client.PingPong)
+}
+
+func (p *proxy) ListMessage(ctx context.Context, req *connect.Request[pb.Request], stream *connect.ServerStream[pb.Message]) error {
+	up, err := p.client.ListMessage(outgoing(ctx, req.Header(), req.Peer()), req.Msg)
+	if err != nil {
+		return connectError(err, nil, nil)
+	}
+	return relay(up, stream.Send, stream.ResponseHeader(), stream.ResponseTrailer())
+}
+
+func (p *proxy) BulkPutMessage(ctx context.Context, stream *connect.ClientStream[pb.Message]) (*connect.Response[emptypb.Empty], error) {
+	up, err := p.client.BulkPutMessage(outgoing(ctx, stream.RequestHeader(), stream.Peer()))
+	if err != nil {
+		return nil, connectError(err, nil, nil)
+	}
+	return closeAndRecv[emptypb.Empty](stream, up)
+}
+
+func (p *proxy) BulkPutMessageV2(ctx context.Context, stream *connect.ClientStream[pb.Message]) (*connect.Response[pb.BulkPutSummary], error) {
+	up, err := p.client.BulkPutMessageV2(outgoing(ctx, stream.RequestHeader(), stream.Peer()))
+	if err != nil {
+		return nil, connectError(err, nil, nil)
+	}
+	return closeAndRecv[pb.BulkPutSummary](stream, up)
+}
+
+func (p *proxy) Chat(ctx context.Context, stream *connect.BidiStream[pb.Message, pb.Message]) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	up, err := p.client.Chat(outgoing(ctx, stream.RequestHeader(), stream.Peer()))
+	if err != nil {
+		return connectError(err, nil, nil)
+	}
+
+	// Requests are passed on as they come, so the stream can go back and
+	// forth. A bad request cancels the call.
+	reqErr := make(chan error, 1)
+	go func() {
+		for {
+			m, err := stream.Receive()
+			if errors.Is(err, io.EOF) {
+				reqErr <- up.CloseSend()
+				return
+			}
+			if err != nil {
+				reqErr <- err
+				cancel()
+				return
+			}
+			// Once the server has ended the call, its status is what counts.
+			if up.Send(m) != nil {
+				reqErr <- nil
+				return
+			}
+		}
+	}()
+
+	err = relay(up, stream.Send, stream.ResponseHeader(), stream.ResponseTrailer())
+	if err != nil {
+		// Report why the call was cancelled rather than the cancellation.
+		select {
+		case rerr := <-reqErr:
+			if rerr != nil {
+				return rerr
+			}
+		default:
+		}
+	}
+	return err
+}
+
+// unary makes a unary call with the message and headers of req.
+func unary[Req, Res any](ctx context.Context, req *connect.Request[Req], call func(context.Context, *Req, ...grpc.CallOption) (*Res, error)) (*connect.Response[Res], error) {
+	var header, trailer metadata.MD
+	m, err := call(outgoing(ctx, req.Header(), req.Peer()), req.Msg, grpc.Header(&header), grpc.Trailer(&trailer))
+	return response(m, header, trailer, err)
+}
+
+// closeAndRecv sends the requests of stream on up, then returns the
+// response of up.
+func closeAndRecv[Res any](stream *connect.ClientStream[pb.Message], up grpc.ClientStream) (*connect.Response[Res], error) {
+	for stream.Receive() {
+		// Once the server has ended the call, RecvMsg returns its status.
+		if up.SendMsg(stream.Msg()) != nil {
+			break
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return nil, err
+	}
+	up.CloseSend()
+	m := new(Res)
+	err := up.RecvMsg(m)
+	header, _ := up.Header()
+	return response(m, header, up.Trailer(), err)
+}
+
+func response[T any](m *T, header, trailer metadata.MD, err error) (*connect.Response[T], error) {
+	if err != nil {
+		return nil, connectError(err, header, trailer)
+	}
+	resp := connect.NewResponse(m)
+	addMetadata(resp.Header(), header)
+	addMetadata(resp.Trailer(), trailer)
+	return resp, nil
+}
+
+// relay sends the responses of up with send until up ends, passing its
+// response metadata on to header and trailer.
+func relay[T any](up grpc.ClientStream, send func(*T) error, header, trailer http.Header) error {
+	for first := true; ; first = false {
+		m := new(T)
+		err := up.RecvMsg(m)
+		if first {
+			md, _ := up.Header()
+			addMetadata(header, md)
+		}
+		if err == io.EOF {
+			addMetadata(trailer, up.Trailer())
+			return nil
+		}
+		if err != nil {
+			return connectError(err, nil, up.Trailer())
+		}
+		if err := send(m); err != nil {
+			return err
+		}
+	}
+}
+
+// outgoing is ctx with the metadata the gateway would send for a request
+// with header from peer.
+func outgoing(ctx context.Context, header http.Header, peer connect.Peer) context.Context {
+	return metadata.NewOutgoingContext(ctx, gateway.OutgoingMetadata(header, peer.Addr))
+}
+
+// connectError is the gRPC error err as a Connect one, with its details and
+// the response metadata.
+func connectError(err error, header, trailer metadata.MD) error {
+	st := status.Convert(err)
+	e := connect.NewError(connect.Code(st.Code()), errors.New(st.Message()))
+	for _, d := range st.Proto().Details {
+		m, err := d.UnmarshalNew()
+		if err != nil {
+			continue
+		}
+		if detail, err := connect.NewErrorDetail(m); err == nil {
+			e.AddDetail(detail)
+		}
+	}
+	addMetadata(e.Meta(), header)
+	addMetadata(e.Meta(), trailer)
+	return e
+}
+
+// addMetadata adds md to h, base64-encoding the values of binary (-bin)
+// keys. The gRPC transport's own keys are left out.
+func addMetadata(h http.Header, md metadata.MD) {
+	for k, values := range md {
+		if k == "content-type" || strings.HasPrefix(k, "grpc-") {
+			continue
+		}
+		for _, v := range values {
+			if strings.HasSuffix(k, "-bin") {
+				v = connect.EncodeBinaryHeader([]byte(v))
+			}
+			h.Add(k, v)
+		}
+	}
+}
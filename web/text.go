@@ -0,0 +1,141 @@
+package web
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// gRPC-Web text is gRPC-Web with its body base64-encoded, the form the
+// official browser client uses by default. The body may be made of several
+// base64 chunks, each padded on its own.
+const (
+	grpcWebType     = "application/grpc-web"
+	grpcWebTextType = "application/grpc-web-text"
+)
+
+// IsGRPCWebText tells whether r is a gRPC-Web text request.
+func IsGRPCWebText(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Content-Type"), grpcWebTextType)
+}
+
+// serveText serves the gRPC-Web text request r with h as the binary gRPC-Web
+// request it encodes, encoding the response in turn.
+func serveText(h http.Handler, w http.ResponseWriter, r *http.Request) {
+	r = r.Clone(r.Context())
+	r.Header.Set("Content-Type", grpcWebType+strings.TrimPrefix(r.Header.Get("Content-Type"), grpcWebTextType))
+	r.Header.Del("Content-Length")
+	r.ContentLength = -1
+	r.Body = &textReader{body: r.Body}
+
+	tw := &textWriter{ResponseWriter: w}
+	tw.enc = base64.NewEncoder(base64.StdEncoding, w)
+	h.ServeHTTP(tw, r)
+	tw.Flush()
+}
+
+// textReader decodes a gRPC-Web text body.
+type textReader struct {
+	body io.ReadCloser
+	in   [4 << 10]byte
+	n    int // bytes of in not decoded yet, less than a quantum
+	out  []byte
+	buf  [3 << 10]byte
+	err  error
+}
+
+func (t *textReader) Read(p []byte) (int, error) {
+	for len(t.out) == 0 {
+		if t.err == io.EOF && t.n > 0 {
+			return 0, io.ErrUnexpectedEOF
+		}
+		if t.err != nil {
+			return 0, t.err
+		}
+		m, err := t.body.Read(t.in[t.n:])
+		t.n += dropNewlines(t.in[t.n : t.n+m])
+		t.err = err
+		whole := t.n - t.n%4
+		n, err := decodeChunks(t.buf[:], t.in[:whole])
+		if err != nil {
+			t.err = err
+		}
+		t.out = t.buf[:n]
+		t.n = copy(t.in[:], t.in[whole:t.n])
+	}
+	n := copy(p, t.out)
+	t.out = t.out[n:]
+	return n, nil
+}
+
+func (t *textReader) Close() error {
+	return t.body.Close()
+}
+
+// dropNewlines removes the line breaks from b in place, as base64 decoding
+// ignores them, returning the length of what is left.
+func dropNewlines(b []byte) int {
+	n := 0
+	for _, c := range b {
+		if c != '\r' && c != '\n' {
+			b[n] = c
+			n++
+		}
+	}
+	return n
+}
+
+// decodeChunks decodes src, whole base64 quanta, into dst, each padded chunk
+// on its own.
+func decodeChunks(dst, src []byte) (int, error) {
+	n := 0
+	for len(src) > 0 {
+		end := len(src)
+		if i := bytes.IndexByte(src, '='); i >= 0 {
+			end = i - i%4 + 4
+		}
+		m, err := base64.StdEncoding.Decode(dst[n:], src[:end])
+		n += m
+		if err != nil {
+			return n, err
+		}
+		src = src[end:]
+	}
+	return n, nil
+}
+
+// textWriter encodes a gRPC-Web response as gRPC-Web text, ending a base64
+// chunk each time it is flushed so streamed messages reach the client.
+type textWriter struct {
+	http.ResponseWriter
+	enc         io.WriteCloser
+	wroteHeader bool
+}
+
+func (w *textWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		h := w.Header()
+		if ct := h.Get("Content-Type"); strings.HasPrefix(ct, grpcWebType) {
+			h.Set("Content-Type", grpcWebTextType+strings.TrimPrefix(ct, grpcWebType))
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *textWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.enc.Write(p)
+}
+
+func (w *textWriter) Flush() {
+	w.enc.Close()
+	w.enc = base64.NewEncoder(base64.StdEncoding, w.ResponseWriter)
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
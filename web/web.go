@@ -0,0 +1,56 @@
+// Package web serves the gRPC-Web and Connect protocols for browsers and
+// curl with connect-go, passing each call on to a gRPC connection so it goes
+// through the same interceptors as native gRPC calls.
+//
+// gRPC-Web is served in binary form (application/grpc-web+proto or +json)
+// and in the base64 text form the official browser client uses by default
+// (application/grpc-web-text), which this package decodes for connect-go.
+// Connect is served for unary calls (application/proto, application/json) and
+// streams (application/connect+proto, application/connect+json). Messages may
+// be gzip-compressed. Bidirectional streams need HTTP/2.
+package web
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/grpc"
+
+	pb "github.com/shin5ok/proto-grpc-simple/pb"
+	"github.com/shin5ok/proto-grpc-simple/pb/pbconnect"
+)
+
+// defaultMaxMessage caps the size of each request message when no other cap
+// is given, as gRPC servers do by default.
+const defaultMaxMessage = 4 << 20
+
+// Handler serves the Simple service over gRPC-Web and Connect.
+type Handler struct {
+	prefix string
+	h      http.Handler
+}
+
+// New serves the Simple service, calling it on conn. Request messages over
+// maxMessage bytes, 4 MiB when it is 0, fail with ResourceExhausted.
+func New(conn grpc.ClientConnInterface, maxMessage int) *Handler {
+	if maxMessage <= 0 {
+		maxMessage = defaultMaxMessage
+	}
+	prefix, h := pbconnect.NewSimpleHandler(&proxy{client: pb.NewSimpleClient(conn)}, connect.WithReadMaxBytes(maxMessage))
+	return &Handler{prefix: prefix, h: h}
+}
+
+// Handles tells whether r is a call of the Simple service, so other
+// requests, such as JSON ones for the gateway, can be sent elsewhere.
+func (h *Handler) Handles(r *http.Request) bool {
+	return r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, h.prefix)
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if IsGRPCWebText(r) {
+		serveText(h.h, w, r)
+		return
+	}
+	h.h.ServeHTTP(w, r)
+}
@@ -0,0 +1,131 @@
+package web
+
+import (
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestConnectError(t *testing.T) {
+	st, _ := status.New(codes.NotFound, "no such message").WithDetails(&errdetails.ErrorInfo{Reason: "MESSAGE_NOT_FOUND"})
+	err := connectError(st.Err(), metadata.Pairs("x-id", "1"), metadata.Pairs("x-raw-bin", "\x00\x01"))
+	var e *connect.Error
+	if !errors.As(err, &e) || e.Code() != connect.CodeNotFound || e.Message() != "no such message" || len(e.Details()) != 1 {
+		t.Fatalf("got %v", err)
+	}
+	if d := e.Details()[0]; d.Type() != "google.rpc.ErrorInfo" {
+		t.Errorf("got detail %s", d.Type())
+	}
+	if e.Meta().Get("X-Id") != "1" || e.Meta().Get("X-Raw-Bin") != "AAE" {
+		t.Errorf("got metadata %v", e.Meta())
+	}
+}
+
+func TestAddMetadata(t *testing.T) {
+	h := http.Header{}
+	addMetadata(h, metadata.Pairs("x-id", "1", "x-raw-bin", "\x00\x01", "content-type", "application/grpc", "grpc-status", "0"))
+	if len(h) != 2 || h.Get("X-Id") != "1" || h.Get("X-Raw-Bin") != "AAE" {
+		t.Errorf("got %v", h)
+	}
+}
+
+func TestCORS(t *testing.T) {
+	h := CORS([]string{"https://app.example/"}, time.Minute, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	do := func(method, origin string, preflight bool) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/simple.Simple/PutMessage", nil)
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		if preflight {
+			r.Header.Set("Access-Control-Request-Method", "POST")
+			r.Header.Set("Access-Control-Request-Headers", "content-type, x-user")
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	w := do(http.MethodOptions, "https://app.example", true)
+	if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Origin") != "https://app.example" ||
+		w.Header().Get("Access-Control-Allow-Headers") != "content-type, x-user" || w.Header().Get("Access-Control-Max-Age") != "60" {
+		t.Errorf("preflight: %d %v", w.Code, w.Header())
+	}
+	if w := do(http.MethodOptions, "https://other.example", true); w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("preflight from another origin: %d %v", w.Code, w.Header())
+	}
+	if w := do(http.MethodPost, "https://app.example", false); w.Code != http.StatusTeapot || w.Header().Get("Access-Control-Expose-Headers") == "" {
+		t.Errorf("call: %d %v", w.Code, w.Header())
+	}
+	if w := do(http.MethodPost, "", false); w.Code != http.StatusTeapot || w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("call without origin: %d %v", w.Code, w.Header())
+	}
+
+	anyOrigin := CORS([]string{"*"}, 0, http.NotFoundHandler())
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.Header.Set("Origin", "http://localhost:3000")
+	anyOrigin.ServeHTTP(w, r)
+	if w.Header().Get("Access-Control-Allow-Origin") != "http://localhost:3000" {
+		t.Errorf("any origin: %v", w.Header())
+	}
+}
+
+func TestIsGRPCContentType(t *testing.T) {
+	for ct, want := range map[string]bool{
+		"application/grpc": true, "application/grpc+proto": true, "application/grpc;charset=utf-8": true,
+		"application/grpc-web": false, "application/grpc-web-text+proto": false, "application/json": false, "": false,
+	} {
+		if got := IsGRPCContentType(ct); got != want {
+			t.Errorf("%q: got %v", ct, got)
+		}
+	}
+}
+
+func TestServeText(t *testing.T) {
+	// Two chunks padded on their own and a line break, read a byte at a time.
+	body := base64.StdEncoding.EncodeToString([]byte("ab")) + base64.StdEncoding.EncodeToString([]byte("cdefg")) + "\n"
+	r := httptest.NewRequest(http.MethodPost, "/simple.Simple/ListMessage", iotest.OneByteReader(strings.NewReader(body)))
+	r.Header.Set("Content-Type", "application/grpc-web-text+proto")
+	w := httptest.NewRecorder()
+	serveText(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/grpc-web+proto" {
+			t.Errorf("request content type %q", ct)
+		}
+		b, err := io.ReadAll(r.Body)
+		if string(b) != "abcdefg" || err != nil {
+			t.Errorf("request body %q %v", b, err)
+		}
+		w.Header().Set("Content-Type", "application/grpc-web+proto")
+		w.Write([]byte("a"))
+		w.(http.Flusher).Flush()
+		w.Write([]byte("bc"))
+	}), w, r)
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/grpc-web-text+proto" {
+		t.Errorf("response content type %q", ct)
+	}
+	if got := w.Body.String(); got != "YQ==YmM=" {
+		t.Errorf("response body %q", got)
+	}
+
+	r = httptest.NewRequest(http.MethodPost, "/simple.Simple/PutMessage", strings.NewReader("YWJj!"))
+	serveText(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.ReadAll(r.Body); err == nil {
+			t.Error("invalid base64 read without error")
+		}
+	}), httptest.NewRecorder(), r)
+}